@@ -0,0 +1,190 @@
+package gopdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+func NewBarcode(barcodeType, value string, width, height float64) *Barcode {
+	b := &Barcode{
+		Value:  value,
+		Width:  width,
+		Height: height,
+	}
+	b.SetType(barcodeType)
+	return b
+}
+
+/*
+Barcode is a barcode drawn in a table cell. Width and Height are the size
+of the bars, including the quiet zones on their sides; by default, bars are
+one point wide per module and 36 points high. ShowText writes the value
+below the bars in the font style of the cell.
+*/
+type Barcode struct {
+	Type     string  `json:"type"`
+	Value    string  `json:"value"`
+	Width    float64 `json:"width"`
+	Height   float64 `json:"height"`
+	ShowText bool    `json:"show_text"`
+}
+
+/*
+SetType sets the symbology of the barcode.
+By default, and currently only, the symbology is Code 128.
+*/
+func (b *Barcode) SetType(barcodeType string) {
+	switch barcodeType {
+	case BarcodeCode128:
+		b.Type = barcodeType
+	default:
+		b.Type = BarcodeCode128
+	}
+}
+
+func (b *Barcode) SetShowText(show bool) {
+	b.ShowText = show
+}
+
+// barcodeQuietZone is the number of blank modules on each side of the bars.
+const barcodeQuietZone = 10
+
+/*
+code128Patterns holds the widths of the bars and spaces of each Code 128
+symbol, in modules, starting with a bar. The last one is the stop symbol.
+*/
+var code128Patterns = [...]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312",
+	"132212", "221213", "221312", "231212", "112232", "122132", "122231", "113222",
+	"123122", "123221", "223211", "221132", "221231", "213212", "223112", "312131",
+	"311222", "321122", "321221", "312212", "322112", "322211", "212123", "212321",
+	"232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121",
+	"313121", "211331", "231131", "213113", "213311", "213131", "311123", "311321",
+	"331121", "312113", "312311", "332111", "314111", "221411", "431111", "111224",
+	"111422", "121124", "121421", "141122", "141221", "112214", "112412", "122114",
+	"122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112",
+	"421211", "212141", "214121", "412121", "111143", "111341", "131141", "114113",
+	"114311", "411113", "411311", "113141", "114131", "311141", "411131", "211412",
+	"211214", "211232", "2331112",
+}
+
+const (
+	code128CodeB  = 100
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+)
+
+/*
+code128 returns the symbols encoding s, with the start symbol, check symbol
+and stop symbol. Digits are encoded in pairs with code set C, and the rest
+with code set B, which only holds printable ASCII characters.
+*/
+func code128(s string) ([]int, error) {
+	for _, r := range s {
+		if r < 32 || r > 126 {
+			return nil, fmt.Errorf("Code 128 cannot encode %q", r)
+		}
+	}
+	var symbols []int
+	digits := strings.Trim(s, "0123456789") == "" && len(s) >= 2
+	rest := s
+	if digits {
+		symbols = append(symbols, code128StartC)
+		for len(rest) >= 2 {
+			symbols = append(symbols, int(rest[0]-'0')*10+int(rest[1]-'0'))
+			rest = rest[2:]
+		}
+		if rest != "" {
+			symbols = append(symbols, code128CodeB)
+		}
+	} else {
+		symbols = append(symbols, code128StartB)
+	}
+	for i := 0; i < len(rest); i++ {
+		symbols = append(symbols, int(rest[i])-32)
+	}
+	check := symbols[0]
+	for i, symbol := range symbols[1:] {
+		check += (i + 1) * symbol
+	}
+	return append(symbols, check%103, code128Stop), nil
+}
+
+/*
+modules returns the widths of the bars and spaces of the barcode, in
+modules, starting with a bar. A value that cannot be encoded is reported
+with a BarcodeError.
+*/
+func (b *Barcode) modules() ([]int, error) {
+	symbols, err := code128(b.Value)
+	if err != nil {
+		return nil, &BarcodeError{Type: b.Type, Value: b.Value, Err: err}
+	}
+	var widths []int
+	for _, symbol := range symbols {
+		for _, w := range code128Patterns[symbol] {
+			widths = append(widths, int(w-'0'))
+		}
+	}
+	return widths, nil
+}
+
+/*
+size returns the size of the bars and, if shown, the text below them.
+*/
+func (b *Barcode) size(p *PDF, style *FontStyle) (float64, float64) {
+	w, h := b.Width, b.Height
+	if w <= 0 {
+		widths, _ := b.modules()
+		n := 2 * barcodeQuietZone
+		for _, m := range widths {
+			n += m
+		}
+		w = float64(n) * p.Engine.PointToUnitConvert(1)
+	}
+	if h <= 0 {
+		h = p.Engine.PointToUnitConvert(36)
+	}
+	if b.ShowText {
+		h += p.lineHeight(style)
+	}
+	return w, h
+}
+
+/*
+draw draws the barcode with its top left corner at x, y, leaving the fill
+color as it was.
+*/
+func (b *Barcode) draw(p *PDF, style *FontStyle, x, y float64) {
+	widths, err := b.modules()
+	if err != nil {
+		p.setError(err)
+		return
+	}
+	w, h := b.size(p, style)
+	if b.ShowText {
+		h -= p.lineHeight(style)
+	}
+	n := 2 * barcodeQuietZone
+	for _, m := range widths {
+		n += m
+	}
+	module := w / float64(n)
+	r, g, bl := p.Engine.GetFillColor()
+	defer p.Engine.SetFillColor(r, g, bl)
+	p.Engine.SetFillColor(0, 0, 0)
+	barX := x + barcodeQuietZone*module
+	for i, m := range widths {
+		if i%2 == 0 {
+			p.Engine.Rect(barX, y, float64(m)*module, h, "F")
+		}
+		barX += float64(m) * module
+	}
+	if b.ShowText {
+		p.Engine.SetXY(x, y+h)
+		p.drawLine(style, w, p.lineHeight(style), b.Value, AlignCenter)
+	}
+}
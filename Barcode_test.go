@@ -0,0 +1,85 @@
+package gopdf
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCode128(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []int
+	}{
+		{"AB", []int{code128StartB, 33, 34, 102, code128Stop}},
+		{"12345678", []int{code128StartC, 12, 34, 56, 78, 47, code128Stop}},
+		{"123", []int{code128StartC, 12, code128CodeB, 19, 65, code128Stop}},
+		{"1", []int{code128StartB, 17, 18, code128Stop}},
+	}
+	for _, tt := range tests {
+		got, err := code128(tt.value)
+		if err != nil {
+			t.Errorf("code128(%q): %v", tt.value, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("code128(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestBarcodeError(t *testing.T) {
+	_, err := NewBarcode("", "café", 0, 0).modules()
+	var barcodeErr *BarcodeError
+	if !errors.As(err, &barcodeErr) {
+		t.Fatalf("modules error = %v, want *BarcodeError", err)
+	}
+	if barcodeErr.Type != BarcodeCode128 || barcodeErr.Value != "café" {
+		t.Errorf("BarcodeError = %+v", barcodeErr)
+	}
+
+	cell := NewCell("", nil, 0, 0)
+	cell.SetBarcode(NewBarcode(BarcodeCode128, "tab\t", 0, 0))
+	err = newTestPDF().WriteTableRows(NewTable(nil).AddRow(cell))
+	if !errors.As(err, &barcodeErr) {
+		t.Errorf("WriteTableRows error = %v, want *BarcodeError", err)
+	}
+}
+
+func TestBarcodeInCell(t *testing.T) {
+	p := newTestPDF()
+	barcode := NewBarcode(BarcodeCode128, "AB", 0, 0)
+	barcode.SetShowText(true)
+	cell := NewCell("", nil, 0, 0)
+	cell.SetBarcode(barcode)
+	if err := p.WriteTableRows(NewTable(nil).AddRow(cell)); err != nil {
+		t.Fatalf("WriteTableRows: %v", err)
+	}
+	content := pageOutput(t, p)[0]
+	// Four symbols of three bars and the stop symbol of four.
+	if got := strings.Count(content, " re f"); got != 16 {
+		t.Errorf("drew %d bars, want 16", got)
+	}
+	findText(t, content, "AB")
+
+	// The default width is a point per module: 4 symbols of 11 modules, the
+	// stop symbol of 13 and the quiet zones of 10.
+	w, _ := barcode.size(p, cell.Style.FontStyle)
+	if want := p.Engine.PointToUnitConvert(77); !near(w, want) {
+		t.Errorf("width = %.2f, want %.2f", w, want)
+	}
+}
+
+func TestBarcodeKeepsFillColor(t *testing.T) {
+	p := newTestPDF()
+	p.Engine.SetFillColor(255, 0, 0)
+	cell := NewCell("", nil, 0, 0)
+	cell.SetBarcode(NewBarcode(BarcodeCode128, "AB", 0, 0))
+	if err := p.WriteTableRows(NewTable(nil).AddRow(cell)); err != nil {
+		t.Fatalf("WriteTableRows: %v", err)
+	}
+	if r, g, b := p.Engine.GetFillColor(); r != 255 || g != 0 || b != 0 {
+		t.Errorf("fill color after the barcode = %d, %d, %d, want red", r, g, b)
+	}
+}
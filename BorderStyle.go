@@ -40,3 +40,25 @@ func (s *BorderStyle) BorderToEngineString() string {
 	}
 	return str
 }
+
+/*
+Draw draws the borders of the style around the given area.
+*/
+func (s *BorderStyle) Draw(p *PDF, x, y, w, h float64) {
+	if !s.Top && !s.Left && !s.Right && !s.Bottom {
+		return
+	}
+	s.SetupBorderColor(p)
+	if s.Top {
+		p.Engine.Line(x, y, x+w, y)
+	}
+	if s.Left {
+		p.Engine.Line(x, y, x, y+h)
+	}
+	if s.Right {
+		p.Engine.Line(x+w, y, x+w, y+h)
+	}
+	if s.Bottom {
+		p.Engine.Line(x, y+h, x+w, y+h)
+	}
+}
@@ -10,11 +10,23 @@ func NewCell(text string, style *CellStyle, width float64, widthPercentage float
 	return c
 }
 
+/*
+Cell is a cell of a table holding text, or content drawn instead of it:
+an image, a barcode, a paragraph of spans or a nested table. The row grows
+to fit the content, which is aligned by the style of the cell.
+*/
 type Cell struct {
 	Text         string     `json:"text"`
 	Style        *CellStyle `json:"style"`
 	Width        float64    `json:"width"`
 	WidthPercent float64    `json:"width_percent"`
+	ColSpan      int        `json:"col_span"`
+	RowSpan      int        `json:"row_span"`
+	Padding      *Padding   `json:"padding"`
+	Image        *CellImage `json:"image"`
+	Barcode      *Barcode   `json:"barcode"`
+	Paragraph    *Paragraph `json:"paragraph"`
+	Table        *Table     `json:"table"`
 }
 
 func (c *Cell) SetStyle(style *CellStyle) {
@@ -24,3 +36,69 @@ func (c *Cell) SetStyle(style *CellStyle) {
 		c.Style = NewCellStyle(nil, nil, nil, "", "")
 	}
 }
+
+/*
+SetColSpan sets the number of columns the cell spans in a Table.
+By default, the cell spans one column.
+*/
+func (c *Cell) SetColSpan(span int) {
+	c.ColSpan = span
+}
+
+/*
+SetRowSpan sets the number of rows the cell spans in a Table.
+The cells of the following rows skip the columns the cell covers.
+By default, the cell spans one row.
+*/
+func (c *Cell) SetRowSpan(span int) {
+	c.RowSpan = span
+}
+
+/*
+SetPadding sets the space between the borders of the cell and its text,
+overriding the padding of its style.
+*/
+func (c *Cell) SetPadding(padding *Padding) {
+	c.Padding = padding
+}
+
+func (c *Cell) SetImage(image *CellImage) {
+	c.Image = image
+}
+
+func (c *Cell) SetBarcode(barcode *Barcode) {
+	c.Barcode = barcode
+}
+
+func (c *Cell) SetParagraph(paragraph *Paragraph) {
+	c.Paragraph = paragraph
+}
+
+/*
+SetTable sets a table drawn in the cell. The nested table is drawn within
+the cell as a whole, without breaking pages.
+*/
+func (c *Cell) SetTable(table *Table) {
+	c.Table = table
+}
+
+func (c *Cell) padding() *Padding {
+	if c.Padding != nil {
+		return c.Padding
+	}
+	return c.Style.Padding
+}
+
+func (c *Cell) colSpan() int {
+	if c.ColSpan < 1 {
+		return 1
+	}
+	return c.ColSpan
+}
+
+func (c *Cell) rowSpan() int {
+	if c.RowSpan < 1 {
+		return 1
+	}
+	return c.RowSpan
+}
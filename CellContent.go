@@ -0,0 +1,129 @@
+package gopdf
+
+import "slices"
+
+/*
+cellContent is content other than text drawn in a table cell.
+*/
+type cellContent interface {
+	// size returns the size of the content in a cell of the given inner width.
+	size(p *PDF, style *CellStyle, width float64) (float64, float64)
+	// widths returns the narrowest and the preferred width of the content.
+	widths(p *PDF, style *CellStyle) (float64, float64)
+	// draw draws the content with its top left corner at x, y.
+	draw(p *PDF, style *CellStyle, x, y, w, h float64)
+}
+
+/*
+content returns the content of the cell drawn instead of its text,
+or nil if it only has text.
+*/
+func (c *Cell) content() cellContent {
+	switch {
+	case c.Table != nil:
+		return tableContent{c.Table}
+	case c.Paragraph != nil:
+		return paragraphContent{c.Paragraph}
+	case c.Image != nil:
+		return imageContent{c.Image}
+	case c.Barcode != nil:
+		return barcodeContent{c.Barcode}
+	}
+	return nil
+}
+
+type imageContent struct{ image *CellImage }
+
+func (c imageContent) size(p *PDF, _ *CellStyle, width float64) (float64, float64) {
+	return c.image.size(p, width)
+}
+
+func (c imageContent) widths(p *PDF, _ *CellStyle) (float64, float64) {
+	w, _ := c.image.size(p, 0)
+	return w, w
+}
+
+func (c imageContent) draw(p *PDF, _ *CellStyle, x, y, w, h float64) {
+	c.image.draw(p, x, y, w, h)
+}
+
+type barcodeContent struct{ barcode *Barcode }
+
+func (c barcodeContent) size(p *PDF, style *CellStyle, _ float64) (float64, float64) {
+	return c.barcode.size(p, style.FontStyle)
+}
+
+func (c barcodeContent) widths(p *PDF, style *CellStyle) (float64, float64) {
+	w, _ := c.barcode.size(p, style.FontStyle)
+	return w, w
+}
+
+func (c barcodeContent) draw(p *PDF, style *CellStyle, x, y, _, _ float64) {
+	c.barcode.draw(p, style.FontStyle, x, y)
+}
+
+type paragraphContent struct{ paragraph *Paragraph }
+
+func (c paragraphContent) size(p *PDF, style *CellStyle, width float64) (float64, float64) {
+	return width, paragraphHeight(p.layoutParagraph(c.paragraph, style.FontStyle, width))
+}
+
+func (c paragraphContent) widths(p *PDF, style *CellStyle) (float64, float64) {
+	return p.paragraphWidths(c.paragraph, style.FontStyle)
+}
+
+func (c paragraphContent) draw(p *PDF, style *CellStyle, x, y, w, _ float64) {
+	p.drawParagraph(p.layoutParagraph(c.paragraph, style.FontStyle, w), x, y, w, style.HAlign)
+}
+
+type tableContent struct{ table *Table }
+
+func (c tableContent) size(p *PDF, _ *CellStyle, width float64) (float64, float64) {
+	block, columns := p.nestedTable(c.table, width)
+	return spanWidth(columns, 0, len(columns)), block.height()
+}
+
+func (c tableContent) widths(p *PDF, _ *CellStyle) (float64, float64) {
+	return p.tableContentWidths(c.table)
+}
+
+func (c tableContent) draw(p *PDF, _ *CellStyle, x, y, w, _ float64) {
+	block, columns := p.nestedTable(c.table, w)
+	p.drawBlockCells(block, x, y, columns)
+}
+
+/*
+nestedTable lays out a table drawn in a cell of the given inner width as a
+single block, styling its header, body and footer rows by its table style.
+The padding and the rows of totals of the table are not used.
+*/
+func (p *PDF) nestedTable(t *Table, width float64) (*tableBlock, []float64) {
+	rows := slices.Concat(t.Header, t.Rows, t.Footer)
+	columns := p.tableWidths(t, rows, width)
+	block, _ := placeCells(rows, len(columns))
+	styles := map[styleKey]*CellStyle{}
+	bodyStart, footerStart := len(t.Header), len(t.Header)+len(t.Rows)
+	for _, part := range []struct {
+		part     tablePart
+		from, to int
+	}{
+		{tableHeader, 0, bodyStart},
+		{tableBody, bodyStart, footerStart},
+		{tableFooter, footerStart, len(rows)},
+	} {
+		var cells []int
+		sub := &tableBlock{}
+		for i, c := range block.cells {
+			if c.Row >= part.from && c.Row < part.to {
+				cells = append(cells, i)
+				sub.cells = append(sub.cells, c)
+			}
+		}
+		t.Style.styleBlock(sub, part.part, -part.from, styles)
+		for j, i := range cells {
+			block.cells[i] = sub.cells[j]
+		}
+	}
+	p.measureBlock(block, columns)
+	return block, columns
+}
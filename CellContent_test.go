@@ -0,0 +1,58 @@
+package gopdf
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestCellImage(t *testing.T) {
+	p := newTestPDF()
+	img := testPNG(t, 40, 20)
+	sized := NewCell("", nil, 0, 0)
+	sized.SetImage(NewCellImageBytes(img, 30, 0))
+	again := NewCell("", nil, 0, 0)
+	again.SetImage(NewCellImageBytes(img, 0, 10))
+	if err := p.WriteTableRows(NewTable(nil).AddRow(sized, again)); err != nil {
+		t.Fatalf("WriteTableRows: %v", err)
+	}
+	// The row is as high as the image scaled to its width.
+	if got := p.Engine.GetY() - p.PageMarginTop; !near(got, 15) {
+		t.Errorf("row height = %.2f, want 15", got)
+	}
+	if n := bytes.Count(output(t, p), []byte("/Subtype /Image")); n != 1 {
+		t.Errorf("the image is embedded %d times, want once", n)
+	}
+
+	wide := NewCellImageBytes(img, 500, 0)
+	if w, h := wide.size(p, 100); !near(w, 100) || !near(h, 50) {
+		t.Errorf("wide image size = %.2f x %.2f, want 100 x 50", w, h)
+	}
+
+	missing := NewCell("", nil, 0, 0)
+	missing.SetImage(NewCellImage("testdata/missing.png", 10, 10))
+	var imageErr *ImageError
+	if err := newTestPDF().WriteTableRows(NewTable(nil).AddRow(missing)); !errors.As(err, &imageErr) {
+		t.Errorf("WriteTableRows error = %v, want *ImageError", err)
+	}
+}
+
+func TestNestedTable(t *testing.T) {
+	p := newTestPDF()
+	inner := NewTable(nil).
+		AddHeaderRow(NewCell("Inner", nil, 0, 0)).
+		AddRow(NewCell("a", nil, 0, 0)).
+		AddRow(NewCell("b", nil, 0, 0))
+	cell := NewCell("", nil, 0, 0)
+	cell.SetTable(inner)
+	p.WriteTableRows(NewTable(nil).AddRow(NewCell("outer", nil, 0, 0), cell).AddRow(NewCell("next", nil, 0, 0), NewCell("", nil, 0, 0)))
+	content := pageOutput(t, p)[0]
+	outer, a, b, next := findText(t, content, "outer"), findText(t, content, "a"), findText(t, content, "b"), findText(t, content, "next")
+	if a.X <= outer.X || !near(findText(t, content, "Inner").Y, outer.Y) {
+		t.Errorf("the nested table is not drawn in the second cell")
+	}
+	// The outer row holds the three rows of the nested table.
+	if !near(outer.Y-next.Y, 36) || !near(a.Y-b.Y, 12) {
+		t.Errorf("rows at %.2f, %.2f, %.2f, %.2f", outer.Y, a.Y, b.Y, next.Y)
+	}
+}
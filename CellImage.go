@@ -0,0 +1,135 @@
+package gopdf
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+func NewCellImage(src string, width, height float64) *CellImage {
+	return &CellImage{
+		Src:    src,
+		Width:  width,
+		Height: height,
+	}
+}
+
+func NewCellImageBytes(b []byte, width, height float64) *CellImage {
+	return &CellImage{
+		Bytes:  b,
+		Width:  width,
+		Height: height,
+	}
+}
+
+/*
+CellImage is an image drawn in a table cell, read from the file Src or from
+Bytes holding a PNG, JPEG or GIF image.
+Without Width and Height the image has its own size, and with one of them
+it keeps its aspect ratio. Images wider than their cell are scaled down.
+*/
+type CellImage struct {
+	Src    string  `json:"src"`
+	Bytes  []byte  `json:"bytes"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+/*
+register hands the image to the engine and returns its name and info,
+or nil if it cannot be read, which fails the document.
+*/
+func (img *CellImage) register(p *PDF) (string, *gofpdf.ImageInfoType) {
+	if p.Err() != nil {
+		return "", nil
+	}
+	name, info, err := img.load(p)
+	if err != nil {
+		p.setError(err)
+		return "", nil
+	}
+	return name, info
+}
+
+/*
+load hands the image to the engine and returns its name and info, or an
+ImageError if it cannot be read. The document is left without error, so
+that the image can be replaced.
+*/
+func (img *CellImage) load(p *PDF) (string, *gofpdf.ImageInfoType, error) {
+	if err := p.Err(); err != nil {
+		return "", nil, err
+	}
+	var name string
+	var info *gofpdf.ImageInfoType
+	if img.Bytes != nil {
+		// Name images by their content, so that each is embedded once.
+		name = fmt.Sprintf("%x", sha1.Sum(img.Bytes))
+		if info = p.Engine.GetImageInfo(name); info == nil {
+			imageType := imageType(img.Bytes)
+			if imageType == "" {
+				return "", nil, &ImageError{Name: name, Err: errors.New("unknown image type")}
+			}
+			info = p.Engine.RegisterImageOptionsReader(name, gofpdf.ImageOptions{ImageType: imageType}, bytes.NewReader(img.Bytes))
+		}
+	} else {
+		name = img.Src
+		info = p.Engine.RegisterImageOptions(name, gofpdf.ImageOptions{})
+	}
+	if p.Engine.Err() {
+		err := p.Engine.Error()
+		p.Engine.ClearError()
+		return "", nil, &ImageError{Name: name, Err: err}
+	}
+	return name, info, nil
+}
+
+/*
+size returns the size of the image in a cell of the given inner width.
+*/
+func (img *CellImage) size(p *PDF, width float64) (float64, float64) {
+	_, info := img.register(p)
+	if info == nil {
+		return 0, 0
+	}
+	w, h := img.Width, img.Height
+	switch {
+	case w > 0 && h > 0:
+	case w > 0:
+		h = w * info.Height() / info.Width()
+	case h > 0:
+		w = h * info.Width() / info.Height()
+	default:
+		w, h = info.Extent()
+	}
+	if w > width && width > 0 {
+		w, h = width, h*width/w
+	}
+	return w, h
+}
+
+func (img *CellImage) draw(p *PDF, x, y, w, h float64) {
+	name, info := img.register(p)
+	if info == nil {
+		return
+	}
+	p.Engine.ImageOptions(name, x, y, w, h, false, gofpdf.ImageOptions{}, 0, "")
+}
+
+/*
+imageType returns the engine image type of image data, or "" if unknown.
+*/
+func imageType(b []byte) string {
+	switch {
+	case bytes.HasPrefix(b, []byte("\x89PNG")):
+		return "png"
+	case bytes.HasPrefix(b, []byte("\xff\xd8")):
+		return "jpg"
+	case bytes.HasPrefix(b, []byte("GIF8")):
+		return "gif"
+	}
+	return ""
+}
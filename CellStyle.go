@@ -16,6 +16,7 @@ type CellStyle struct {
 	FillColor   *RGB         `json:"fill_color"`
 	HAlign      string       `json:"h_align"`
 	VAlign      string       `json:"v_align"`
+	Padding     *Padding     `json:"padding"`
 }
 
 func (s *CellStyle) SetFontStyle(style *FontStyle) {
@@ -38,9 +39,14 @@ func (s *CellStyle) SetFillColor(color *RGB) {
 	s.FillColor = color
 }
 
+/*
+SetHAlign sets the horizontal alignment of the text of the cell. Justified
+text is stretched to the width of the cell, except for the last line of
+each paragraph. By default, text is aligned left.
+*/
 func (s *CellStyle) SetHAlign(align string) {
 	switch align {
-	case AlignLeft, AlignCenter, AlignRight:
+	case AlignLeft, AlignCenter, AlignRight, AlignJustify:
 		s.HAlign = align
 	default:
 		s.HAlign = AlignLeft
@@ -56,6 +62,15 @@ func (s *CellStyle) SetVAlign(align string) {
 	}
 }
 
+/*
+SetPadding sets the space between the borders of table cells and their text.
+By default, only the cell margin of the engine separates text from the
+left and right borders.
+*/
+func (s *CellStyle) SetPadding(padding *Padding) {
+	s.Padding = padding
+}
+
 func (s *CellStyle) SetupFillColor(p *PDF) {
 	if s.FillColor != nil {
 		p.Engine.SetFillColor(s.FillColor.R, s.FillColor.G, s.FillColor.B)
@@ -66,6 +81,11 @@ func (s *CellStyle) ToAlignEngineString() string {
 	return s.HAlignToEngineString() + s.VAlignToEngineString()
 }
 
+/*
+HAlignToEngineString returns the horizontal alignment of the style for the
+engine. Justified text is "J", which the engine only stretches in
+MultiCell; cells of a Table are justified by the table itself.
+*/
 func (s *CellStyle) HAlignToEngineString() string {
 	switch s.HAlign {
 	case AlignLeft:
@@ -74,6 +94,8 @@ func (s *CellStyle) HAlignToEngineString() string {
 		return "C"
 	case AlignRight:
 		return "R"
+	case AlignJustify:
+		return "J"
 	default:
 		return "L"
 	}
@@ -0,0 +1,81 @@
+package gopdf
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrFontUnavailable   = errors.New("font data is unavailable")
+	ErrFontParse         = errors.New("font data cannot be parsed")
+	ErrFontNotRegistered = errors.New("font is not registered")
+)
+
+/*
+EngineError wraps an error recorded by the underlying gofpdf engine.
+*/
+type EngineError struct {
+	Err error
+}
+
+func (e *EngineError) Error() string {
+	return fmt.Sprintf("gopdf: engine: %v", e.Err)
+}
+
+func (e *EngineError) Unwrap() error {
+	return e.Err
+}
+
+/*
+FontError reports a font that cannot be loaded into the document.
+*/
+type FontError struct {
+	Family string
+	Style  string
+	Err    error
+}
+
+func (e *FontError) Error() string {
+	if e.Style == "" {
+		return fmt.Sprintf("gopdf: font %q: %v", e.Family, e.Err)
+	}
+	return fmt.Sprintf("gopdf: font %q (%s): %v", e.Family, e.Style, e.Err)
+}
+
+func (e *FontError) Unwrap() error {
+	return e.Err
+}
+
+/*
+ImageError reports an image that cannot be read or decoded.
+Name is the file path, or the generated name for images written from bytes.
+*/
+type ImageError struct {
+	Name string
+	Err  error
+}
+
+func (e *ImageError) Error() string {
+	return fmt.Sprintf("gopdf: image %q: %v", e.Name, e.Err)
+}
+
+func (e *ImageError) Unwrap() error {
+	return e.Err
+}
+
+/*
+BarcodeError reports a barcode value that its symbology cannot encode.
+*/
+type BarcodeError struct {
+	Type  string
+	Value string
+	Err   error
+}
+
+func (e *BarcodeError) Error() string {
+	return fmt.Sprintf("gopdf: barcode %s %q: %v", e.Type, e.Value, e.Err)
+}
+
+func (e *BarcodeError) Unwrap() error {
+	return e.Err
+}
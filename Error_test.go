@@ -0,0 +1,125 @@
+package gopdf
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestImageErrorIsStickyAndReturnedByOutput(t *testing.T) {
+	p := New()
+	err := p.WriteImage("testdata/missing.png", 10, 10, false)
+	var imageErr *ImageError
+	if !errors.As(err, &imageErr) {
+		t.Fatalf("WriteImage error = %v, want *ImageError", err)
+	}
+	if imageErr.Name != "testdata/missing.png" {
+		t.Errorf("ImageError.Name = %q", imageErr.Name)
+	}
+	if got := p.WriteText("after", nil); got != err {
+		t.Errorf("WriteText after error = %v, want the first error", got)
+	}
+	if got := p.Err(); got != err {
+		t.Errorf("Err = %v, want the first error", got)
+	}
+	if _, got := p.Bytes(); got != err {
+		t.Errorf("Bytes error = %v, want the first error", got)
+	}
+	if b := p.ToBytes(); b != nil {
+		t.Errorf("ToBytes = %d bytes, want nil", len(b))
+	}
+	path := filepath.Join(t.TempDir(), "out.pdf")
+	if got := p.ToFile(path); got != err {
+		t.Errorf("ToFile error = %v, want the first error", got)
+	}
+	if _, statErr := os.Stat(path); !errors.Is(statErr, fs.ErrNotExist) {
+		t.Errorf("ToFile created a file for a failed document")
+	}
+}
+
+func TestToFileKeepsFileOnFailure(t *testing.T) {
+	// The footer is drawn when the document is output, with a font that is
+	// not registered.
+	p := New()
+	p.SetFooter(NewPageRegion(20, func(pdf *PDF, page int) {
+		style := NewFontStyle("", 10, 0, nil, false, false, false)
+		style.FontFamily = "No Such Family"
+		pdf.WriteText("footer", style)
+	}))
+	p.WriteText("body", nil)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.pdf")
+	if err := os.WriteFile(path, []byte("previous"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.ToFile(path); !errors.Is(err, ErrFontNotRegistered) {
+		t.Fatalf("ToFile error = %v, want ErrFontNotRegistered", err)
+	}
+	if b, err := os.ReadFile(path); err != nil || string(b) != "previous" {
+		t.Errorf("ToFile changed the file already at the path: %q, %v", b, err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("ToFile left %d files, want only the previous one", len(entries))
+	}
+
+	// A document that is written replaces the file, keeping its mode.
+	if err := New().ToFile(path); err != nil {
+		t.Fatalf("ToFile: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0o600 || info.Size() <= int64(len("previous")) {
+		t.Errorf("ToFile wrote %v, %v, want the document with mode 0600", info, err)
+	}
+}
+
+func TestImageBytesError(t *testing.T) {
+	p := New()
+	err := p.WriteImageBytes([]byte("not an image"), 10, 10, false)
+	var imageErr *ImageError
+	if !errors.As(err, &imageErr) {
+		t.Fatalf("WriteImageBytes error = %v, want *ImageError", err)
+	}
+}
+
+func TestUnregisteredFontIsFontError(t *testing.T) {
+	p := New()
+	style := NewFontStyle("", 12, 0, nil, false, false, false)
+	style.FontFamily = "No Such Family"
+	err := p.WriteText("text", style)
+	var fontErr *FontError
+	if !errors.As(err, &fontErr) {
+		t.Fatalf("WriteText error = %v, want *FontError", err)
+	}
+	if fontErr.Family != "No Such Family" || !errors.Is(err, ErrFontNotRegistered) {
+		t.Errorf("FontError = %+v, want ErrFontNotRegistered for the family", fontErr)
+	}
+}
+
+func TestEngineErrorIsWrapped(t *testing.T) {
+	p := New()
+	p.Engine.SetErrorf("broken")
+	err := p.WriteText("text", nil)
+	var engineErr *EngineError
+	if !errors.As(err, &engineErr) {
+		t.Fatalf("WriteText error = %v, want *EngineError", err)
+	}
+	if engineErr.Err.Error() != "broken" {
+		t.Errorf("EngineError.Err = %v", engineErr.Err)
+	}
+}
+
+func TestOutputWithoutError(t *testing.T) {
+	p := New()
+	if err := p.WriteText("hello", nil); err != nil {
+		t.Fatalf("WriteText: %v", err)
+	}
+	b, err := p.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+	if len(b) < 5 || string(b[:5]) != "%PDF-" {
+		t.Errorf("output does not start with a PDF header")
+	}
+}
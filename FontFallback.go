@@ -0,0 +1,125 @@
+package gopdf
+
+import (
+	"strings"
+	"unicode"
+)
+
+/*
+textRun is a piece of text drawn with a single font family.
+*/
+type textRun struct {
+	Style *FontStyle
+	Text  string
+}
+
+/*
+textRuns splits text into runs by font family, giving each character the
+first family of the style and its fallbacks that has a glyph for it.
+Spaces stay in the current run, and characters no family covers are drawn
+with the style's own family.
+*/
+func (s *FontStyle) textRuns(text string) []textRun {
+	if len(s.Fallback) == 0 {
+		return []textRun{{Style: s, Text: text}}
+	}
+	families := append([]string{s.FontFamily}, s.Fallback...)
+	styles := make([]*FontStyle, len(families))
+	var runs []textRun
+	var b strings.Builder
+	current := -1
+	for _, r := range text {
+		i := current
+		if current < 0 || !unicode.IsSpace(r) {
+			i = s.familyFor(families, r)
+		}
+		if i != current && b.Len() > 0 {
+			runs = append(runs, textRun{Style: styles[current], Text: b.String()})
+			b.Reset()
+		}
+		if styles[i] == nil {
+			styles[i] = s.withFamily(families[i])
+		}
+		current = i
+		b.WriteRune(r)
+	}
+	if b.Len() > 0 {
+		runs = append(runs, textRun{Style: styles[current], Text: b.String()})
+	}
+	return runs
+}
+
+func (s *FontStyle) familyFor(families []string, r rune) int {
+	for i, family := range families {
+		if s.familyHasRune(family, r) {
+			return i
+		}
+	}
+	return 0
+}
+
+/*
+familyHasRune reports whether the face of family used by the style has a
+glyph for r. Core fonts only cover ASCII, as text is not translated to their
+single-byte encoding.
+*/
+func (s *FontStyle) familyHasRune(family string, r rune) bool {
+	if isCoreFont(family) {
+		return r <= unicode.MaxASCII
+	}
+	style := ""
+	if s.Bold {
+		style += "B"
+	}
+	if s.Italic {
+		style += "I"
+	}
+	face := defaultFontRegistry.face(family, style)
+	if face == nil {
+		face = defaultFontRegistry.face(family, faceStyle(strings.ReplaceAll(style, "I", "")))
+	}
+	if face == nil {
+		return false
+	}
+	_, info, err := face.get()
+	return err == nil && info.HasRune(r)
+}
+
+func (s *FontStyle) withFamily(family string) *FontStyle {
+	if family == s.FontFamily && len(s.Fallback) == 0 {
+		return s
+	}
+	style := *s
+	style.FontFamily = family
+	style.Fallback = nil
+	return &style
+}
+
+/*
+needsLineLayout reports whether text in the style must be laid out and drawn
+line by line, instead of by the engine.
+*/
+func (s *FontStyle) needsLineLayout() bool {
+	return len(s.Fallback) > 0 || s.syntheticItalic()
+}
+
+/*
+textMeasure returns a function measuring text in the style, switching fonts
+for fallback runs as needed, and page placeholders as they are laid out. It
+leaves the engine with any of the fonts of the style set up.
+*/
+func (p *PDF) textMeasure(style *FontStyle) func(string) float64 {
+	style.setup(p)
+	current := style.FontFamily
+	return func(text string) float64 {
+		var w float64
+		for _, run := range style.textRuns(p.layoutText(text)) {
+			if run.Style.FontFamily != current {
+				run.Style.setup(p)
+				current = run.Style.FontFamily
+			}
+			w += p.Engine.GetStringWidth(run.Text)
+		}
+		return w
+	}
+}
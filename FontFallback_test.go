@@ -0,0 +1,66 @@
+package gopdf
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func registerTestFallback(t *testing.T) {
+	t.Helper()
+	err := RegisterFontFamily(os.DirFS("testdata"), "Test Fallback", FontFaces{Regular: "DejaVuSansCondensed.ttf"})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTextRuns(t *testing.T) {
+	registerTestFallback(t)
+	style := NewFontStyle(FontFamilyHelvetica, 12, 0, nil, false, false, false)
+	if err := style.SetFallback("Test Fallback"); err != nil {
+		t.Fatal(err)
+	}
+	const h, f = FontFamilyHelvetica, "Test Fallback"
+	tests := []struct {
+		text string
+		want []string // family and text of each run
+	}{
+		{"Anna", []string{h, "Anna"}},
+		{"Hi Жанна!", []string{h, "Hi ", f, "Жанна", h, "!"}},
+		{"Жанна Anna", []string{f, "Жанна ", h, "Anna"}},
+		// No family has the glyph, so it stays in the style's own family.
+		{"中", []string{h, "中"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, run := range style.textRuns(tt.text) {
+			got = append(got, run.Style.FontFamily, run.Text)
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("textRuns(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestWriteTextWithFallback(t *testing.T) {
+	registerTestFallback(t)
+	style := NewFontStyle(FontFamilyHelvetica, 12, 0, nil, false, false, false)
+	style.SetFallback("Test Fallback")
+	p := newTestPDF()
+	if err := p.WriteText("Hi Жанна", style); err != nil {
+		t.Fatalf("WriteText: %v", err)
+	}
+	doc := output(t, p)
+	if !bytes.Contains(doc, []byte("/BaseFont /Helvetica")) || !bytes.Contains(doc, []byte("/FontFile2")) {
+		t.Errorf("the document does not use both fonts")
+	}
+	// The fallback run follows the Latin run on the same line.
+	drawn := texts(pageContents(t, doc)[0])
+	if len(drawn) != 2 || drawn[0].Text != "Hi " {
+		t.Fatalf("drawn text = %+v, want two runs", drawn)
+	}
+	if !near(drawn[1].Y, drawn[0].Y) || drawn[1].X <= drawn[0].X {
+		t.Errorf("fallback run at %+v after %+v", drawn[1], drawn[0])
+	}
+}
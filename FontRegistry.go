@@ -0,0 +1,301 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/METADIV-GO/gopdf/ttf_bytes"
+)
+
+/*
+fontRegistry holds the TrueType fonts that documents can use.
+Registered fonts are read and checked when registered, and the bundled fonts
+when first used; their data and rune coverage are then shared by every
+document. Each document only hands a font to its engine when a FontStyle
+selects it.
+*/
+type fontRegistry struct {
+	mu       sync.RWMutex
+	faces    map[string]*fontFace
+	families map[string]string
+}
+
+type fontFace struct {
+	Family string
+	Style  string
+
+	load func() ([]byte, error)
+	once sync.Once
+	data []byte
+	info *trueTypeInfo
+	err  error
+}
+
+var defaultFontRegistry = newFontRegistry()
+
+func init() {
+	defaultFontRegistry.add(FontFamilyNotoSansTC, FaceRegular, ttf_bytes.NotoSansTCRegularBytes)
+	defaultFontRegistry.add(FontFamilyNotoSansTC, FaceBold, ttf_bytes.NotoSansTCBoldBytes)
+	defaultFontRegistry.add(FontFamilyNotoSansSC, FaceRegular, ttf_bytes.NotoSansSCRegularBytes)
+	defaultFontRegistry.add(FontFamilyNotoSansSC, FaceBold, ttf_bytes.NotoSansSCBoldBytes)
+}
+
+/*
+FontFaces names the font files of a family for RegisterFontFamily.
+Faces left empty are not registered.
+*/
+type FontFaces struct {
+	Regular    string `json:"regular"`
+	Bold       string `json:"bold"`
+	Italic     string `json:"italic"`
+	BoldItalic string `json:"bold_italic"`
+}
+
+/*
+RegisterFont registers a TrueType font face from bytes, so that any FontStyle
+can use it by family name. The style is one of FaceRegular, FaceBold,
+FaceItalic or FaceBoldItalic. Registering a face again replaces it for
+documents that have not used it yet. The font is copied and parsed at once:
+a font that cannot be read or parsed returns a FontError and leaves the face
+registered before, if any, in place.
+*/
+func RegisterFont(family, style string, b []byte) error {
+	return registerFont(family, style, func() ([]byte, error) {
+		return bytes.Clone(b), nil
+	})
+}
+
+/*
+RegisterFontFile registers a TrueType font face from a file path.
+*/
+func RegisterFontFile(family, style, path string) error {
+	return registerFont(family, style, func() ([]byte, error) {
+		return os.ReadFile(path)
+	})
+}
+
+/*
+RegisterFontFS registers a TrueType font face from a file in fsys,
+such as an embed.FS.
+*/
+func RegisterFontFS(fsys fs.FS, family, style, name string) error {
+	return registerFont(family, style, fsFont(fsys, name))
+}
+
+func fsFont(fsys fs.FS, name string) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		return fs.ReadFile(fsys, name)
+	}
+}
+
+/*
+RegisterFontFamily registers the faces of a family from files in fsys.
+Use os.DirFS to register files from a directory.
+*/
+func RegisterFontFamily(fsys fs.FS, family string, faces FontFaces) error {
+	var loaded []*fontFace
+	for _, face := range []struct{ style, name string }{
+		{FaceRegular, faces.Regular},
+		{FaceBold, faces.Bold},
+		{FaceItalic, faces.Italic},
+		{FaceBoldItalic, faces.BoldItalic},
+	} {
+		if face.name == "" {
+			continue
+		}
+		f, err := loadFontFace(family, face.style, fsFont(fsys, face.name))
+		if err != nil {
+			return err
+		}
+		loaded = append(loaded, f)
+	}
+	// Only register the family once every face is loaded.
+	for _, f := range loaded {
+		defaultFontRegistry.put(f)
+	}
+	return nil
+}
+
+/*
+IsFontFamilyAvailable reports whether family is a core font or has at least
+one registered face. The bundled Noto families are always registered, but
+their font files are supplied separately: without them, writing with these
+families fails with ErrFontUnavailable.
+*/
+func IsFontFamilyAvailable(family string) bool {
+	return isCoreFont(family) || defaultFontRegistry.hasFamily(family)
+}
+
+/*
+RegisteredFontFamilies returns the names of the registered font families,
+including the bundled Noto fonts but not the core fonts.
+*/
+func RegisteredFontFamilies() []string {
+	return defaultFontRegistry.familyNames()
+}
+
+func registerFont(family, style string, load func() ([]byte, error)) error {
+	face, err := loadFontFace(family, style, load)
+	if err != nil {
+		return err
+	}
+	defaultFontRegistry.put(face)
+	return nil
+}
+
+/*
+loadFontFace reads and parses a font face to register.
+*/
+func loadFontFace(family, style string, load func() ([]byte, error)) (*fontFace, error) {
+	if family == "" {
+		return nil, &FontError{Family: family, Style: style, Err: ErrFontUnavailable}
+	}
+	if isCoreFont(family) {
+		return nil, &FontError{Family: family, Style: style, Err: fmt.Errorf("%q is a core font name", family)}
+	}
+	face := newFontFace(family, style, load)
+	if _, _, err := face.get(); err != nil {
+		return nil, &FontError{Family: family, Style: face.Style, Err: err}
+	}
+	return face, nil
+}
+
+func newFontRegistry() *fontRegistry {
+	return &fontRegistry{faces: map[string]*fontFace{}, families: map[string]string{}}
+}
+
+func newFontFace(family, style string, load func() ([]byte, error)) *fontFace {
+	return &fontFace{Family: family, Style: faceStyle(style), load: load}
+}
+
+func (r *fontRegistry) add(family, style string, load func() ([]byte, error)) {
+	r.put(newFontFace(family, style, load))
+}
+
+func (r *fontRegistry) put(face *fontFace) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.faces[fontKey(face.Family, face.Style)] = face
+	r.families[strings.ToLower(face.Family)] = face.Family
+}
+
+func (r *fontRegistry) hasFamily(family string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.families[strings.ToLower(family)]
+	return ok
+}
+
+func (r *fontRegistry) familyNames() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.families))
+	for _, name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *fontRegistry) face(family, style string) *fontFace {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.faces[fontKey(family, style)]
+}
+
+/*
+get returns the font data and its mapped runes, loading and parsing them on
+first use.
+*/
+func (f *fontFace) get() ([]byte, *trueTypeInfo, error) {
+	f.once.Do(func() {
+		b, err := f.load()
+		if err != nil {
+			f.err = fmt.Errorf("%w: %w", ErrFontUnavailable, err)
+			return
+		}
+		info, err := parseTrueType(b)
+		if err != nil {
+			f.err = fmt.Errorf("%w: %w", ErrFontParse, err)
+			return
+		}
+		f.data, f.info, f.load = b, info, nil
+	})
+	return f.data, f.info, f.err
+}
+
+/*
+loadFont hands a registered font to the engine the first time the document
+uses it. Core fonts are built into the engine and need no loading. The font
+data and its mapped runes are shared, but the engine parses the font again
+for each document: its parsed fonts are internal to it and track the glyphs
+each document uses, to embed only those.
+*/
+func (p *PDF) loadFont(family, style string) {
+	if p.Err() != nil || isCoreFont(family) {
+		return
+	}
+	style = faceStyle(style)
+	if p.Engine.GetFontDesc(family, style).Ascent != 0 {
+		return
+	}
+	face := defaultFontRegistry.face(family, style)
+	if face == nil {
+		p.setError(&FontError{Family: family, Style: style, Err: ErrFontNotRegistered})
+		return
+	}
+	b, _, err := face.get()
+	if err != nil {
+		p.setError(&FontError{Family: family, Style: style, Err: err})
+		return
+	}
+	p.addUTF8Font(family, style, b)
+}
+
+func (p *PDF) addUTF8Font(family, style string, b []byte) {
+	if p.Err() != nil {
+		return
+	}
+	p.Engine.AddUTF8FontFromBytes(family, style, b)
+	if p.Engine.Err() {
+		p.setError(&FontError{Family: family, Style: style, Err: p.Engine.Error()})
+		return
+	}
+	// The engine only prints parse failures, so check that the font was added.
+	if p.Engine.GetFontDesc(family, style).Ascent == 0 {
+		p.setError(&FontError{Family: family, Style: style, Err: ErrFontParse})
+	}
+}
+
+func isCoreFont(family string) bool {
+	switch strings.ToLower(family) {
+	case "courier", "helvetica", "arial", "times", "symbol", "zapfdingbats":
+		return true
+	}
+	return false
+}
+
+/*
+faceStyle keeps the bold and italic flags of an engine style string,
+as underline and strikeout are drawn by the engine and need no font face.
+*/
+func faceStyle(style string) string {
+	style = strings.ToUpper(style)
+	var s string
+	if strings.Contains(style, "B") {
+		s += "B"
+	}
+	if strings.Contains(style, "I") {
+		s += "I"
+	}
+	return s
+}
+
+func fontKey(family, style string) string {
+	return strings.ToLower(family) + faceStyle(style)
+}
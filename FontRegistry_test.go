@@ -0,0 +1,187 @@
+package gopdf
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/METADIV-GO/gopdf/ttf_bytes"
+)
+
+func TestMissingBundledFontIsUnavailable(t *testing.T) {
+	if _, err := ttf_bytes.ReadFile(ttf_bytes.FileNotoSansTCRegular); err == nil {
+		t.Skip("the Noto fonts are bundled into this build")
+	}
+	p := New()
+	err := p.WriteText("text", NewFontStyle(FontFamilyNotoSansTC, 12, 0, nil, false, false, false))
+	var fontErr *FontError
+	if !errors.As(err, &fontErr) {
+		t.Fatalf("WriteText error = %v, want *FontError", err)
+	}
+	if !errors.Is(err, ErrFontUnavailable) || !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("error = %v, want ErrFontUnavailable for a missing file", err)
+	}
+}
+
+func TestRegisteredFontIsLoadedOnce(t *testing.T) {
+	var loads int
+	err := registerFont("Test Shared", FaceRegular, func() ([]byte, error) {
+		loads++
+		return os.ReadFile("testdata/DejaVuSansCondensed.ttf")
+	})
+	if err != nil {
+		t.Fatalf("registerFont: %v", err)
+	}
+	unused := New()
+	unused.WriteText("core font", nil)
+	if unused.Engine.GetFontDesc("Test Shared", "").Ascent != 0 {
+		t.Errorf("the font was handed to a document that does not use it")
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p := New()
+			if err := p.WriteText("shared", NewFontStyle("Test Shared", 12, 0, nil, false, false, false)); err != nil {
+				t.Errorf("WriteText: %v", err)
+			}
+			if _, err := p.Bytes(); err != nil {
+				t.Errorf("Bytes: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if loads != 1 {
+		t.Errorf("font data read %d times, want once", loads)
+	}
+}
+
+func TestRegisterFontErrors(t *testing.T) {
+	for name, err := range map[string]error{
+		"empty family": RegisterFont("", FaceRegular, nil),
+		"core family":  RegisterFont(FontFamilyCourier, FaceRegular, nil),
+	} {
+		var fontErr *FontError
+		if !errors.As(err, &fontErr) {
+			t.Errorf("%s: error = %v, want *FontError", name, err)
+		}
+	}
+}
+
+func TestRegisterFontFailures(t *testing.T) {
+	b, err := os.ReadFile("testdata/DejaVuSansCondensed.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterFont("Test Kept", FaceRegular, b); err != nil {
+		t.Fatalf("RegisterFont: %v", err)
+	}
+	for name, tt := range map[string]struct {
+		err  error
+		want error
+	}{
+		"bad bytes":    {RegisterFont("Test Kept", FaceRegular, []byte("not a font")), ErrFontParse},
+		"missing file": {RegisterFontFile("Test Kept", FaceRegular, "testdata/missing.ttf"), ErrFontUnavailable},
+		"missing fs":   {RegisterFontFS(os.DirFS("testdata"), "Test Kept", FaceRegular, "missing.ttf"), ErrFontUnavailable},
+	} {
+		var fontErr *FontError
+		if !errors.As(tt.err, &fontErr) || !errors.Is(tt.err, tt.want) {
+			t.Errorf("%s: error = %v, want a FontError for %v", name, tt.err, tt.want)
+		}
+	}
+	// The working face is not replaced by the bad ones.
+	if err := New().WriteText("text", NewFontStyle("Test Kept", 12, 0, nil, false, false, false)); err != nil {
+		t.Errorf("WriteText: %v", err)
+	}
+}
+
+func TestRegisterFontCopiesBytes(t *testing.T) {
+	b, err := os.ReadFile("testdata/DejaVuSansCondensed.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterFont("Test Copied", FaceRegular, b); err != nil {
+		t.Fatalf("RegisterFont: %v", err)
+	}
+	clear(b)
+	if err := New().WriteText("text", NewFontStyle("Test Copied", 12, 0, nil, false, false, false)); err != nil {
+		t.Errorf("WriteText after the bytes changed: %v", err)
+	}
+}
+
+func TestRegisterFontFamily(t *testing.T) {
+	err := RegisterFontFamily(os.DirFS("testdata"), "Test Family", FontFaces{Regular: "DejaVuSansCondensed.ttf"})
+	if err != nil {
+		t.Fatalf("RegisterFontFamily: %v", err)
+	}
+	if !slices.Contains(RegisteredFontFamilies(), "Test Family") || !IsFontFamilyAvailable("test family") {
+		t.Errorf("registered family is not listed as available")
+	}
+	style := NewFontStyle("", 12, 0, nil, false, false, false)
+	if err := style.SetFontFamily("Test Family"); err != nil {
+		t.Errorf("SetFontFamily: %v", err)
+	}
+	p := New()
+	if err := p.WriteText("Привет", style); err != nil {
+		t.Fatalf("WriteText: %v", err)
+	}
+	doc := output(t, p)
+	if !bytes.Contains(doc, []byte("/FontFile2")) || !bytes.Contains(doc, []byte("/BaseFont /utf8test#20family")) {
+		t.Errorf("the registered font is not embedded")
+	}
+
+	// The family has no bold face.
+	bold := *style
+	bold.Bold = true
+	err = New().WriteText("bold", &bold)
+	if !errors.Is(err, ErrFontNotRegistered) {
+		t.Errorf("WriteText in a missing face: error = %v, want ErrFontNotRegistered", err)
+	}
+}
+
+func TestRegisterFontFS(t *testing.T) {
+	b, err := os.ReadFile("testdata/DejaVuSansCondensed.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsys := fstest.MapFS{"fonts/italic.ttf": {Data: b}}
+	if err := RegisterFontFS(fsys, "Test FS", FaceItalic, "fonts/italic.ttf"); err != nil {
+		t.Fatalf("RegisterFontFS: %v", err)
+	}
+	if face := defaultFontRegistry.face("Test FS", FaceItalic); face == nil {
+		t.Errorf("the italic face is not registered")
+	}
+	if err := RegisterFontFS(fsys, "Test FS", FaceBold, "fonts/missing.ttf"); !errors.Is(err, ErrFontUnavailable) {
+		t.Errorf("RegisterFontFS of a missing file: error = %v, want ErrFontUnavailable", err)
+	}
+	if face := defaultFontRegistry.face("Test FS", FaceBold); face != nil {
+		t.Errorf("the missing bold face is registered")
+	}
+	err = RegisterFontFamily(fsys, "Test FS Family", FontFaces{Regular: "fonts/italic.ttf", Bold: "fonts/missing.ttf"})
+	if !errors.Is(err, ErrFontUnavailable) {
+		t.Errorf("RegisterFontFamily with a missing face: error = %v, want ErrFontUnavailable", err)
+	}
+	if IsFontFamilyAvailable("Test FS Family") {
+		t.Errorf("the family is registered although a face is missing")
+	}
+}
+
+func TestSetFontFamilyUnknown(t *testing.T) {
+	style := NewFontStyle("", 12, 0, nil, false, false, false)
+	err := style.SetFontFamily("Test Unknown")
+	if !errors.Is(err, ErrFontNotRegistered) {
+		t.Errorf("SetFontFamily error = %v, want ErrFontNotRegistered", err)
+	}
+	if style.FontFamily != "Test Unknown" {
+		t.Errorf("FontFamily = %q, want the unknown family kept", style.FontFamily)
+	}
+	if err := style.SetFallback(FontFamilyTimes, "Test Unknown"); !errors.Is(err, ErrFontNotRegistered) {
+		t.Errorf("SetFallback error = %v, want ErrFontNotRegistered", err)
+	}
+}
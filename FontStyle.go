@@ -1,5 +1,7 @@
 package gopdf
 
+import "fmt"
+
 func NewFontStyle(
 	fontFamily string,
 	fontSize float64,
@@ -19,45 +21,114 @@ func NewFontStyle(
 }
 
 type FontStyle struct {
-	FontFamily string  `json:"font_family"`
-	FontSize   float64 `json:"font_size"`
-	LineHeight float64 `json:"line_height"`
-	FontColor  *RGB    `json:"font_color"`
-	Bold       bool    `json:"bold"`
-	Strikeout  bool    `json:"strikeout"`
-	Underline  bool    `json:"underline"`
+	FontFamily string   `json:"font_family"`
+	Fallback   []string `json:"fallback"`
+	FontSize   float64  `json:"font_size"`
+	LineHeight float64  `json:"line_height"`
+	FontColor  *RGB     `json:"font_color"`
+	Bold       bool     `json:"bold"`
+	Italic     bool     `json:"italic"`
+	Strikeout  bool     `json:"strikeout"`
+	Underline  bool     `json:"underline"`
 }
 
 /*
 Setup sets the font style for the PDF.
 */
 func (s *FontStyle) Setup(pdf *PDF) {
+	styleStr := s.engineStyle()
+	pdf.loadFont(s.FontFamily, styleStr)
+	pdf.Engine.SetTextColor(s.FontColor.R, s.FontColor.G, s.FontColor.B)
+	pdf.Engine.SetFont(s.FontFamily, styleStr, s.FontSize)
+	pdf.fontKey = s.key(styleStr)
+	style := *s
+	pdf.fontStyle = &style
+}
+
+/*
+setup sets the font style for the PDF unless it is already the current one,
+to avoid repeating font changes in the page content.
+*/
+func (s *FontStyle) setup(pdf *PDF) {
+	if pdf.fontKey != s.key(s.engineStyle()) {
+		s.Setup(pdf)
+	}
+}
+
+/*
+keepFont returns a function that sets up the font style and text colour in
+use again, after measuring or drawing with other font styles. Nothing is
+set up again if the style in use was never changed.
+*/
+func (p *PDF) keepFont() func() {
+	fontKey, fontStyle := p.fontKey, p.fontStyle
+	r, g, b := p.Engine.GetTextColor()
+	return func() {
+		if p.fontKey == fontKey && fontKey != "" {
+			return
+		}
+		p.Engine.SetTextColor(r, g, b)
+		p.fontKey = ""
+		if fontKey != "" && fontStyle != nil {
+			fontStyle.Setup(p)
+		}
+	}
+}
+
+func (s *FontStyle) engineStyle() string {
 	styleStr := ""
 	if s.Bold {
 		styleStr += "B"
 	}
+	if s.Italic && !s.syntheticItalic() {
+		styleStr += "I"
+	}
 	if s.Strikeout {
 		styleStr += "S"
 	}
 	if s.Underline {
 		styleStr += "U"
 	}
-	pdf.Engine.SetTextColor(s.FontColor.R, s.FontColor.G, s.FontColor.B)
-	pdf.Engine.SetFont(s.FontFamily, styleStr, s.FontSize)
+	return styleStr
+}
+
+func (s *FontStyle) key(styleStr string) string {
+	return fmt.Sprintf("%s|%s|%t|%g|%d,%d,%d", s.FontFamily, styleStr, s.Italic, s.FontSize, s.FontColor.R, s.FontColor.G, s.FontColor.B)
 }
 
 /*
 SetFontFamily sets the font family for the PDF.
-By default, the font family is Helvetica.
+The family is either a core font, such as FontFamilyHelvetica, or a family
+added with RegisterFont. By default, the font family is Helvetica.
+An unknown family is kept, so that it can still be registered before the
+style is used, but it is reported with ErrFontNotRegistered.
 */
-func (s *FontStyle) SetFontFamily(fontFamily string) {
-	switch fontFamily {
-	case FontFamilyCourier, FontFamilyHelvetica, FontFamilyTimes,
-		FontFamilySymbol, FontFamilyNotoSansTC, FontFamilyNotoSansSC:
-		s.FontFamily = fontFamily
-	default:
+func (s *FontStyle) SetFontFamily(fontFamily string) error {
+	if fontFamily == "" {
 		s.FontFamily = FontFamilyHelvetica
+		return nil
+	}
+	s.FontFamily = fontFamily
+	if !IsFontFamilyAvailable(fontFamily) {
+		return &FontError{Family: fontFamily, Err: ErrFontNotRegistered}
 	}
+	return nil
+}
+
+/*
+SetFallback sets the font families used, in order, for characters that the
+font family has no glyph for, such as CJK or emoji in Latin text.
+Each run of characters is drawn with the first family that has its glyphs.
+By default, there are no fallback families.
+*/
+func (s *FontStyle) SetFallback(fontFamilies ...string) error {
+	s.Fallback = fontFamilies
+	for _, family := range fontFamilies {
+		if !IsFontFamilyAvailable(family) {
+			return &FontError{Family: family, Err: ErrFontNotRegistered}
+		}
+	}
+	return nil
 }
 
 /*
@@ -73,7 +144,8 @@ func (s *FontStyle) SetFontSize(fontSize float64) {
 }
 
 /*
-SetLineHeight sets the line height for the PDF.
+SetLineHeight sets the line height for the PDF, in points like the font
+size, whatever the unit of the document.
 By default, the line height same as the font size.
 */
 func (s *FontStyle) SetLineHeight(lineHeight float64) {
@@ -104,6 +176,15 @@ func (s *FontStyle) SetBold(bold bool) {
 	s.Bold = bold
 }
 
+/*
+SetItalic sets the font style to italic.
+By default, the font style is not italic.
+Registered fonts without an italic face are slanted instead.
+*/
+func (s *FontStyle) SetItalic(italic bool) {
+	s.Italic = italic
+}
+
 /*
 SetStrikeout sets the font style to strikeout.
 By default, the font style is not strikeout.
@@ -119,3 +200,26 @@ By default, the font style is not underline.
 func (s *FontStyle) SetUnderline(underline bool) {
 	s.Underline = underline
 }
+
+/*
+syntheticItalic reports whether the style is italic but its family has no
+italic face, as with the bundled Noto fonts, so that text must be slanted.
+*/
+func (s *FontStyle) syntheticItalic() bool {
+	if !s.Italic || isCoreFont(s.FontFamily) {
+		return false
+	}
+	style := "I"
+	if s.Bold {
+		style = "BI"
+	}
+	return defaultFontRegistry.face(s.FontFamily, style) == nil
+}
+
+/*
+lineHeight returns the line height of a font style in the unit of the
+document.
+*/
+func (p *PDF) lineHeight(style *FontStyle) float64 {
+	return p.Engine.PointToUnitConvert(style.LineHeight)
+}
@@ -0,0 +1,74 @@
+package gopdf
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestItalicCoreFonts(t *testing.T) {
+	p := newTestPDF()
+	for _, s := range []struct {
+		family string
+		bold   bool
+	}{{FontFamilyHelvetica, false}, {FontFamilyHelvetica, true}, {FontFamilyTimes, false}} {
+		style := NewFontStyle(s.family, 12, 0, nil, s.bold, false, false)
+		style.SetItalic(true)
+		p.WriteText("italic", style)
+	}
+	doc := output(t, p)
+	for _, name := range []string{"/Helvetica-Oblique", "/Helvetica-BoldOblique", "/Times-Italic"} {
+		if !bytes.Contains(doc, []byte("/BaseFont "+name)) {
+			t.Errorf("font %s is not used", name)
+		}
+	}
+	if strings.Contains(pageContents(t, doc)[0], " cm") {
+		t.Errorf("core italics are slanted")
+	}
+}
+
+func TestItalicRegisteredFonts(t *testing.T) {
+	fsys := os.DirFS("testdata")
+	if err := RegisterFontFamily(fsys, "Test Upright", FontFaces{Regular: "DejaVuSansCondensed.ttf"}); err != nil {
+		t.Fatal(err)
+	}
+	faces := FontFaces{Regular: "DejaVuSansCondensed.ttf", Italic: "DejaVuSansCondensed.ttf"}
+	if err := RegisterFontFamily(fsys, "Test Italic", faces); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		family  string
+		slanted bool
+	}{{"Test Upright", true}, {"Test Italic", false}} {
+		style := NewFontStyle(tt.family, 12, 0, nil, false, false, false)
+		style.SetItalic(true)
+		if got := style.syntheticItalic(); got != tt.slanted {
+			t.Errorf("%s: syntheticItalic = %v, want %v", tt.family, got, tt.slanted)
+		}
+		p := newTestPDF()
+		if err := p.WriteText("italic", style); err != nil {
+			t.Fatalf("%s: WriteText: %v", tt.family, err)
+		}
+		content := pageOutput(t, p)[0]
+		if got := strings.Contains(content, " cm"); got != tt.slanted {
+			t.Errorf("%s: text slanted = %v, want %v", tt.family, got, tt.slanted)
+		}
+	}
+}
+
+func TestSlantedItalicIsSetUpApart(t *testing.T) {
+	if err := RegisterFontFamily(os.DirFS("testdata"), "Test Upright", FontFaces{Regular: "DejaVuSansCondensed.ttf"}); err != nil {
+		t.Fatal(err)
+	}
+	italic := NewFontStyle("Test Upright", 12, 0, nil, false, false, false)
+	italic.SetItalic(true)
+	upright := NewFontStyle("Test Upright", 12, 0, nil, false, false, false)
+	p := newTestPDF()
+	italic.setup(p)
+	slanted := p.fontKey
+	upright.setup(p)
+	if p.fontKey == slanted {
+		t.Errorf("the upright style kept the italic style set up")
+	}
+}
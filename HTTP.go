@@ -0,0 +1,110 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const ContentTypePDF = "application/pdf"
+
+/*
+BuildFunc builds the document served by Handler for a request,
+together with the file name presented to the client.
+*/
+type BuildFunc func(r *http.Request) (pdf *PDF, filename string, err error)
+
+/*
+Handler returns an http.Handler that builds a document per request with fn
+and writes it like WriteHTTP. If fn fails or returns no document, or the
+document cannot be output, the handler responds with 500 Internal Server
+Error.
+*/
+func Handler(fn BuildFunc, inline bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pdf, filename, err := fn(r)
+		var b bytes.Buffer
+		if err == nil && pdf != nil {
+			_, err = pdf.WriteTo(&b)
+		}
+		if err != nil || pdf == nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		// A failure to send the response cannot be reported to the client.
+		writePDFResponse(w, &b, filename, inline)
+	})
+}
+
+/*
+WriteHTTP writes the document as an HTTP response with the Content-Type,
+Content-Disposition and Content-Length headers set.
+The document is rendered before any header is written, so if it has an error
+the response is left untouched for the caller to report.
+*/
+func (p *PDF) WriteHTTP(w http.ResponseWriter, filename string, inline bool) error {
+	var b bytes.Buffer
+	if _, err := p.WriteTo(&b); err != nil {
+		return err
+	}
+	return writePDFResponse(w, &b, filename, inline)
+}
+
+/*
+writePDFResponse writes a rendered document with its headers.
+*/
+func writePDFResponse(w http.ResponseWriter, b *bytes.Buffer, filename string, inline bool) error {
+	h := w.Header()
+	h.Set("Content-Type", ContentTypePDF)
+	h.Set("Content-Disposition", ContentDisposition(filename, inline))
+	h.Set("Content-Length", strconv.Itoa(b.Len()))
+	_, err := b.WriteTo(w)
+	return err
+}
+
+/*
+ContentDisposition returns a Content-Disposition header value for filename.
+Non-ASCII file names are sent as a UTF-8 "filename*" parameter (RFC 6266)
+along with an ASCII fallback for older clients.
+*/
+func ContentDisposition(filename string, inline bool) string {
+	disposition := "attachment"
+	if inline {
+		disposition = "inline"
+	}
+	if filename == "" {
+		return disposition
+	}
+	var fallback strings.Builder
+	for _, r := range filename {
+		if r == '"' || r == '\\' || r < 0x20 || r > 0x7e {
+			fallback.WriteByte('_')
+		} else {
+			fallback.WriteRune(r)
+		}
+	}
+	value := disposition + `; filename="` + fallback.String() + `"`
+	if fallback.String() == filename {
+		return value
+	}
+	var encoded strings.Builder
+	for i := 0; i < len(filename); i++ {
+		if c := filename[i]; isAttrChar(c) {
+			encoded.WriteByte(c)
+		} else {
+			fmt.Fprintf(&encoded, "%%%02X", c)
+		}
+	}
+	return value + "; filename*=UTF-8''" + encoded.String()
+}
+
+// isAttrChar reports whether c may appear unescaped in an RFC 5987 value.
+func isAttrChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$&+-.^_`|~", c) >= 0
+}
@@ -0,0 +1,126 @@
+package gopdf
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestWriteToCountsBytes(t *testing.T) {
+	p := New()
+	p.WriteText("hello", nil)
+	var b bytes.Buffer
+	n, err := p.WriteTo(&b)
+	if err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	if n != int64(b.Len()) || n == 0 {
+		t.Errorf("WriteTo returned %d, wrote %d bytes", n, b.Len())
+	}
+}
+
+func TestWriteHTTPHeaders(t *testing.T) {
+	p := New()
+	p.WriteText("hello", nil)
+	rec := httptest.NewRecorder()
+	if err := p.WriteHTTP(rec, "report.pdf", true); err != nil {
+		t.Fatalf("WriteHTTP: %v", err)
+	}
+	h := rec.Header()
+	if got := h.Get("Content-Type"); got != ContentTypePDF {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := h.Get("Content-Disposition"); got != `inline; filename="report.pdf"` {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+	if got := h.Get("Content-Length"); got != strconv.Itoa(rec.Body.Len()) {
+		t.Errorf("Content-Length = %q, body is %d bytes", got, rec.Body.Len())
+	}
+}
+
+func TestWriteHTTPLeavesResponseOnError(t *testing.T) {
+	p := New()
+	p.WriteImage("testdata/missing.png", 10, 10, false)
+	rec := httptest.NewRecorder()
+	if err := p.WriteHTTP(rec, "report.pdf", false); err == nil {
+		t.Fatal("WriteHTTP succeeded for a failed document")
+	}
+	if len(rec.Header()) != 0 || rec.Body.Len() != 0 {
+		t.Errorf("WriteHTTP wrote headers %v and %d bytes", rec.Header(), rec.Body.Len())
+	}
+}
+
+func TestContentDisposition(t *testing.T) {
+	tests := []struct {
+		filename string
+		inline   bool
+		want     string
+	}{
+		{"", false, "attachment"},
+		{"a.pdf", false, `attachment; filename="a.pdf"`},
+		{`say "hi".pdf`, true, `inline; filename="say _hi_.pdf"; filename*=UTF-8''say%20%22hi%22.pdf`},
+		{"報告.pdf", false, `attachment; filename="__.pdf"; filename*=UTF-8''%E5%A0%B1%E5%91%8A.pdf`},
+	}
+	for _, tt := range tests {
+		if got := ContentDisposition(tt.filename, tt.inline); got != tt.want {
+			t.Errorf("ContentDisposition(%q, %v) = %q, want %q", tt.filename, tt.inline, got, tt.want)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	h := Handler(func(r *http.Request) (*PDF, string, error) {
+		p := New()
+		p.WriteText(r.URL.Query().Get("text"), nil)
+		return p, "a.pdf", nil
+	}, false)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/?text=hi", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != ContentTypePDF {
+		t.Fatalf("status %d, Content-Type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !bytes.HasPrefix(rec.Body.Bytes(), []byte("%PDF-")) {
+		t.Errorf("body is not a PDF document")
+	}
+}
+
+func TestHandlerErrors(t *testing.T) {
+	tests := map[string]BuildFunc{
+		"build error": func(r *http.Request) (*PDF, string, error) {
+			return nil, "", errors.New("no data")
+		},
+		"no document": func(r *http.Request) (*PDF, string, error) {
+			return nil, "", nil
+		},
+		"document error": func(r *http.Request) (*PDF, string, error) {
+			p := New()
+			p.WriteImage("testdata/missing.png", 10, 10, false)
+			return p, "a.pdf", nil
+		},
+		"output error": func(r *http.Request) (*PDF, string, error) {
+			// The footer is drawn when the document is output, with a
+			// font that is not registered.
+			p := New()
+			p.SetFooter(NewPageRegion(20, func(pdf *PDF, page int) {
+				style := NewFontStyle("", 10, 0, nil, false, false, false)
+				style.FontFamily = "No Such Family"
+				pdf.WriteText("footer", style)
+			}))
+			p.WriteText("body", nil)
+			return p, "a.pdf", nil
+		},
+	}
+	for name, fn := range tests {
+		rec := httptest.NewRecorder()
+		Handler(fn, true).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("%s: status %d, want 500", name, rec.Code)
+		}
+		if rec.Header().Get("Content-Type") == ContentTypePDF {
+			t.Errorf("%s: response declared a PDF document", name)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package gopdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+/*
+newTestPDF returns a document with uncompressed page content, so that tests
+can read what was drawn.
+*/
+func newTestPDF(layout ...*PageLayout) *PDF {
+	p := New(layout...)
+	p.Engine.SetCompression(false)
+	return p
+}
+
+/*
+output returns the bytes of a document, failing the test on error.
+*/
+func output(t *testing.T, p *PDF) []byte {
+	t.Helper()
+	b, err := p.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+	return b
+}
+
+var contentsRef = regexp.MustCompile(`/Contents (\d+) 0 R`)
+
+/*
+pageContents returns the content stream of each page of an uncompressed
+document, in page order.
+*/
+func pageContents(t *testing.T, doc []byte) []string {
+	t.Helper()
+	var pages []string
+	for _, m := range contentsRef.FindAllSubmatch(doc, -1) {
+		n, _ := strconv.Atoi(string(m[1]))
+		start := bytes.Index(doc, []byte(fmt.Sprintf("\n%d 0 obj\n", n)))
+		if start < 0 {
+			t.Fatalf("content object %d not found", n)
+		}
+		stream := bytes.Index(doc[start:], []byte("stream\n"))
+		end := bytes.Index(doc[start:], []byte("\nendstream"))
+		if stream < 0 || end < stream {
+			t.Fatalf("content stream %d not found", n)
+		}
+		pages = append(pages, string(doc[start+stream+len("stream\n"):start+end]))
+	}
+	return pages
+}
+
+/*
+pageOutput writes a document and returns the content of its pages.
+*/
+func pageOutput(t *testing.T, p *PDF) []string {
+	t.Helper()
+	return pageContents(t, output(t, p))
+}
+
+var textOp = regexp.MustCompile(`BT ([\d.-]+) ([\d.-]+) Td \(((?:\\.|[^\\)])*)\)Tj ET`)
+
+/*
+drawnText is a piece of text drawn on a page, at its position in PDF
+coordinates, from the bottom of the page.
+*/
+type drawnText struct {
+	X, Y float64
+	Text string
+}
+
+/*
+texts returns the text drawn in a content stream of core fonts, in drawing
+order.
+*/
+func texts(content string) []drawnText {
+	var out []drawnText
+	for _, m := range textOp.FindAllStringSubmatch(content, -1) {
+		x, _ := strconv.ParseFloat(m[1], 64)
+		y, _ := strconv.ParseFloat(m[2], 64)
+		text := regexp.MustCompile(`\\(.)`).ReplaceAllString(m[3], "$1")
+		out = append(out, drawnText{X: x, Y: y, Text: text})
+	}
+	return out
+}
+
+/*
+findText returns the first text drawn in a content stream that equals s.
+*/
+func findText(t *testing.T, content, s string) drawnText {
+	t.Helper()
+	for _, d := range texts(content) {
+		if d.Text == s {
+			return d
+		}
+	}
+	t.Fatalf("text %q not drawn in:\n%s", s, content)
+	return drawnText{}
+}
+
+/*
+before returns the part of a content stream before the first drawing of s.
+*/
+func before(t *testing.T, content, s string) string {
+	t.Helper()
+	i := bytes.Index([]byte(content), []byte("("+s+")Tj"))
+	if i < 0 {
+		t.Fatalf("text %q not drawn in:\n%s", s, content)
+	}
+	return content[:i]
+}
+
+/*
+testPNG returns a PNG image of the given size in pixels.
+*/
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: 200, A: 255})
+		}
+	}
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func near(a, b float64) bool {
+	d := a - b
+	return d < 0.02 && d > -0.02
+}
@@ -0,0 +1,53 @@
+package gopdf
+
+/*
+Length is a length in points, made from any unit with Pt, Mm, Cm and In, so
+that lengths in different units can be added together. To converts it to the
+unit of a document.
+*/
+type Length float64
+
+func Pt(v float64) Length {
+	return Length(v)
+}
+
+func Mm(v float64) Length {
+	return Length(v * unitPoints(UnitMillimeter))
+}
+
+func Cm(v float64) Length {
+	return Length(v * unitPoints(UnitCentimeter))
+}
+
+func In(v float64) Length {
+	return Length(v * unitPoints(UnitInch))
+}
+
+/*
+To returns the length in the given unit, such as the Unit of a PageLayout.
+*/
+func (l Length) To(unit string) float64 {
+	return float64(l) / unitPoints(unit)
+}
+
+/*
+Length returns a length in the unit of the document.
+*/
+func (p *PDF) Length(l Length) float64 {
+	return l.To(p.PageLayout.unit())
+}
+
+/*
+unitPoints returns the number of points in a unit. Unknown units are points.
+*/
+func unitPoints(unit string) float64 {
+	switch unit {
+	case UnitMillimeter:
+		return 72 / 25.4
+	case UnitCentimeter:
+		return 72 / 2.54
+	case UnitInch:
+		return 72
+	}
+	return 1
+}
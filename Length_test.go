@@ -0,0 +1,62 @@
+package gopdf
+
+import "testing"
+
+func TestLength(t *testing.T) {
+	tests := []struct {
+		length Length
+		unit   string
+		want   float64
+	}{
+		{In(1), UnitPoint, 72},
+		{In(1), UnitMillimeter, 25.4},
+		{Cm(2.54), UnitInch, 1},
+		{Mm(10) + Cm(1), UnitCentimeter, 2},
+		{Pt(36), UnitInch, 0.5},
+		{Pt(5), "unknown", 5},
+	}
+	for _, tt := range tests {
+		if got := tt.length.To(tt.unit); !near(got, tt.want) {
+			t.Errorf("%v pt to %s = %v, want %v", float64(tt.length), tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestDocumentUnit(t *testing.T) {
+	layout := NewPageLayout(OrientationPortrait, PaperA4)
+	layout.SetUnit(UnitMillimeter)
+	layout.PageMargin = NewPageMargin(20, 15, 15, 25)
+	p := newTestPDF(layout)
+	if !near(p.PageWidth, 210) || !near(p.PageHeight, 297) {
+		t.Errorf("page is %.2f x %.2f mm", p.PageWidth, p.PageHeight)
+	}
+	if !near(p.PageBodyWidth, 180) || p.PageMarginTop != 20 {
+		t.Errorf("body is %.2f mm wide below %.2f mm", p.PageBodyWidth, p.PageMarginTop)
+	}
+	if got := p.Length(Cm(1)); !near(got, 10) {
+		t.Errorf("Length(1cm) = %.2f mm", got)
+	}
+	// Font sizes and line heights stay in points.
+	style := NewFontStyle("", 12, 18, nil, false, false, false)
+	if got := p.lineHeight(style); !near(got, 18*25.4/72) {
+		t.Errorf("line height = %.2f mm, want 18pt", got)
+	}
+	p.WriteText("text", style)
+	d := findText(t, pageOutput(t, p)[0], "text")
+	if !near(d.X, (15+p.Engine.GetCellMargin())*72/25.4) {
+		t.Errorf("text at x %.2f pt, want inside the 15 mm margin", d.X)
+	}
+}
+
+func TestPageLayoutMarginInOtherUnit(t *testing.T) {
+	doc := NewPageLayout(OrientationPortrait, PaperA4)
+	doc.SetUnit(UnitMillimeter)
+	page := NewPageLayout(OrientationLandscape, PaperA4)
+	page.SetUnit(UnitInch)
+	page.PageMargin = NewPageMargin(1, 1, 1, 1)
+	p := New(doc)
+	p.AddPageWithLayout(page)
+	if !near(p.PageMarginLeft, 25.4) || !near(p.PageWidth, 297) {
+		t.Errorf("margin %.2f mm on a page %.2f mm wide, want 25.4 and 297", p.PageMarginLeft, p.PageWidth)
+	}
+}
@@ -0,0 +1,519 @@
+package gopdf
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+func NewMarkdownStyle(text *FontStyle) *MarkdownStyle {
+	if text == nil {
+		text = NewFontStyle("", 0, 0, nil, false, false, false)
+	}
+	s := &MarkdownStyle{
+		Text:       text,
+		QuoteColor: NewRGB(200, 200, 200),
+		LinkColor:  NewRGB(0, 0, 204),
+		RuleColor:  NewRGB(160, 160, 160),
+		Align:      AlignLeft,
+	}
+	for _, scale := range []float64{2, 1.6, 1.35, 1.15, 1, 0.9} {
+		heading := *text
+		heading.Bold = true
+		heading.FontSize *= scale
+		heading.LineHeight *= scale
+		s.Headings = append(s.Headings, &heading)
+	}
+	code := *text
+	code.FontFamily = FontFamilyCourier
+	s.Code = &code
+	s.CodeBlock = NewCellStyle(&code, nil, NewRGB(245, 245, 245), AlignLeft, AlignTop)
+	quote := *text
+	quote.Italic = true
+	quote.FontColor = NewRGB(96, 96, 96)
+	s.Quote = &quote
+	header := *text
+	header.Bold = true
+	border := NewBorderStyle(true, true, true, true, NewRGB(160, 160, 160))
+	s.TableHeader = NewCellStyle(&header, border, NewRGB(230, 230, 230), AlignLeft, AlignTop)
+	s.TableCell = NewCellStyle(text, border, nil, AlignLeft, AlignTop)
+	return s
+}
+
+/*
+MarkdownStyle maps the elements of Markdown to the styles they are written
+with by WriteMarkdown. Headings holds the styles of heading levels 1 to 6,
+Code the style of code spans, scaled with the text around them, and
+CodeBlock the font, fill color and padding of code blocks. Block quotes are
+written in Quote beside bars of QuoteColor, links in LinkColor and
+underlined, and tables with TableHeader and TableCell.
+Paragraphs are aligned by Align. Lists and quotes are indented by Indent,
+and blocks are separated by Spacing, in the unit of the document; when
+zero, they are twice and half the line height of Text.
+*/
+type MarkdownStyle struct {
+	Text        *FontStyle   `json:"text"`
+	Headings    []*FontStyle `json:"headings"`
+	Code        *FontStyle   `json:"code"`
+	CodeBlock   *CellStyle   `json:"code_block"`
+	Quote       *FontStyle   `json:"quote"`
+	QuoteColor  *RGB         `json:"quote_color"`
+	LinkColor   *RGB         `json:"link_color"`
+	RuleColor   *RGB         `json:"rule_color"`
+	TableHeader *CellStyle   `json:"table_header"`
+	TableCell   *CellStyle   `json:"table_cell"`
+	Align       string       `json:"align"`
+	Indent      float64      `json:"indent"`
+	Spacing     float64      `json:"spacing"`
+}
+
+/*
+SetHeading sets the style of a heading level, from 1 to 6.
+*/
+func (s *MarkdownStyle) SetHeading(level int, style *FontStyle) {
+	if level >= 1 && level <= len(s.Headings) {
+		s.Headings[level-1] = style
+	}
+}
+
+func (s *MarkdownStyle) SetAlign(align string) {
+	s.Align = align
+}
+
+func (s *MarkdownStyle) SetIndent(indent float64) {
+	s.Indent = indent
+}
+
+func (s *MarkdownStyle) SetSpacing(spacing float64) {
+	s.Spacing = spacing
+}
+
+func (s *MarkdownStyle) heading(level int) *FontStyle {
+	if level >= 1 && level <= len(s.Headings) && s.Headings[level-1] != nil {
+		return s.Headings[level-1]
+	}
+	return s.Text
+}
+
+/*
+WriteMarkdown writes a Markdown document across the page body, breaking
+pages between lines: headings, paragraphs with emphasis, code spans and
+links, ordered and unordered lists, block quotes, code blocks, pipe tables,
+thematic breaks and images read from files. Elements are written in the
+given style, or in a style derived from the default font style if it is
+nil.
+
+Images that cannot be read are written as their alternative text instead.
+Their ImageErrors are returned, joined, but do not fail the document, which
+can still be output.
+*/
+func (p *PDF) WriteMarkdown(text string, style *MarkdownStyle) error {
+	if err := p.Err(); err != nil {
+		return err
+	}
+	if style == nil {
+		style = NewMarkdownStyle(p.DefaultFontStyle)
+	}
+	w := &markdownWriter{
+		pdf:   p,
+		style: style,
+		text:  style.Text,
+		x:     p.PageMarginLeft,
+		width: p.PageBodyWidth,
+	}
+	err := w.blocks(parseMarkdown(text), w.spacing())
+	p.Engine.SetX(p.PageMarginLeft)
+	if err != nil {
+		return err
+	}
+	return errors.Join(w.imageErrs...)
+}
+
+/*
+markdownWriter writes the blocks of a Markdown document within a column
+from x, which lists and quotes indent. Text is the style of paragraphs,
+which is the quote style within quotes, and bars the x of the bars of the
+enclosing quotes.
+*/
+type markdownWriter struct {
+	pdf   *PDF
+	style *MarkdownStyle
+	text  *FontStyle
+	x     float64
+	width float64
+	bars  []float64
+	level int
+
+	imageErrs []error // images written as their alternative text
+}
+
+func (w *markdownWriter) indent() float64 {
+	if w.style.Indent > 0 {
+		return w.style.Indent
+	}
+	return 2 * w.pdf.lineHeight(w.style.Text)
+}
+
+func (w *markdownWriter) spacing() float64 {
+	if w.style.Spacing > 0 {
+		return w.style.Spacing
+	}
+	return w.pdf.lineHeight(w.style.Text) / 2
+}
+
+/*
+blocks writes blocks separated by spacing.
+*/
+func (w *markdownWriter) blocks(blocks []*mdBlock, spacing float64) error {
+	for i, b := range blocks {
+		if i > 0 {
+			w.space(spacing)
+		}
+		if err := w.block(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *markdownWriter) block(b *mdBlock) error {
+	switch b.Kind {
+	case mdParagraph:
+		inlines := parseInlines(b.Text)
+		if len(inlines) == 1 && inlines[0].Image != "" {
+			w.image(inlines[0].Image, inlines[0].Text)
+		} else {
+			w.paragraph(w.spans(inlines, w.text), w.text, w.style.Align)
+		}
+	case mdHeading:
+		style := w.style.heading(b.Level)
+		w.paragraph(w.spans(parseInlines(b.Text), style), style, AlignLeft)
+	case mdCode:
+		w.code(b.Text)
+	case mdQuote:
+		return w.quote(b.Children)
+	case mdList:
+		return w.list(b)
+	case mdTable:
+		return w.table(b)
+	case mdRule:
+		w.rule()
+	}
+	return w.pdf.Err()
+}
+
+/*
+spans returns a paragraph of inline text in a base style. Images in text
+are written as their alternative text.
+*/
+func (w *markdownWriter) spans(inlines []mdInline, base *FontStyle) *Paragraph {
+	par := NewParagraph()
+	for _, in := range inlines {
+		style := *base
+		if in.Code && w.style.Code != nil {
+			// Code spans are scaled with the text around them, as in headings.
+			scale := base.FontSize / w.style.Text.FontSize
+			style = *w.style.Code
+			style.FontSize *= scale
+			style.LineHeight *= scale
+			style.Bold = style.Bold || base.Bold
+		}
+		style.Bold = style.Bold || in.Bold
+		style.Italic = style.Italic || in.Italic
+		style.Strikeout = style.Strikeout || in.Strike
+		if in.Link != "" {
+			if w.style.LinkColor != nil {
+				style.FontColor = w.style.LinkColor
+			}
+			style.Underline = true
+		}
+		span := NewSpan(in.Text, &style)
+		span.SetLink(in.Link)
+		par.AddSpan(span)
+	}
+	return par
+}
+
+/*
+fit breaks the page if a block of height h does not fit below the current
+position, and returns the Y to write it at.
+*/
+func (w *markdownWriter) fit(h float64) float64 {
+	p := w.pdf
+	if p.Engine.GetY()+h > p.pageBreakTrigger() && p.Engine.GetY() > p.pageTop() && !p.inRegion {
+		p.continuePage()
+	}
+	return p.Engine.GetY()
+}
+
+/*
+space moves down between blocks, unless at the top of a page.
+*/
+func (w *markdownWriter) space(h float64) {
+	p := w.pdf
+	y := p.Engine.GetY()
+	if y <= p.pageTop() {
+		return
+	}
+	if y+h <= p.pageBreakTrigger() {
+		w.drawBars(y, h)
+	}
+	p.Engine.SetXY(w.x, y+h)
+}
+
+/*
+drawBars draws the bars of the enclosing quotes beside a line.
+*/
+func (w *markdownWriter) drawBars(y, h float64) {
+	if len(w.bars) == 0 || w.style.QuoteColor == nil {
+		return
+	}
+	e := w.pdf.Engine
+	r, g, b := e.GetDrawColor()
+	lineWidth := e.GetLineWidth()
+	e.SetDrawColor(w.style.QuoteColor.R, w.style.QuoteColor.G, w.style.QuoteColor.B)
+	e.SetLineWidth(w.barWidth())
+	for _, x := range w.bars {
+		e.Line(x, y, x, y+h)
+	}
+	e.SetDrawColor(r, g, b)
+	e.SetLineWidth(lineWidth)
+}
+
+func (w *markdownWriter) barWidth() float64 {
+	return w.pdf.Engine.PointToUnitConvert(w.style.Text.FontSize / 4)
+}
+
+func (w *markdownWriter) paragraph(par *Paragraph, style *FontStyle, align string) {
+	p := w.pdf
+	for _, line := range p.layoutParagraph(par, style, w.width) {
+		y := w.fit(line.Height)
+		w.drawBars(y, line.Height)
+		p.drawParagraphLine(line, w.x, y, w.width, align)
+		p.Engine.SetXY(w.x, y+line.Height)
+	}
+}
+
+/*
+code writes a code block line by line on its fill color, wrapping lines
+wider than the column.
+*/
+func (w *markdownWriter) code(text string) {
+	p := w.pdf
+	cell := w.style.CodeBlock
+	style := cell.FontStyle
+	top, left, right, bottom := w.codeInsets()
+	width := w.width - left - right
+	measure := p.textMeasure(style)
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		lines = append(lines, wrapText(measure, line, width, width)...)
+	}
+	lineHeight := p.lineHeight(style)
+	cm := p.Engine.GetCellMargin()
+	p.Engine.SetCellMargin(0)
+	for i, line := range lines {
+		h, textY := lineHeight, 0.0
+		if i == 0 {
+			h, textY = h+top, top
+		}
+		if i == len(lines)-1 {
+			h += bottom
+		}
+		y := w.fit(h)
+		w.drawBars(y, h)
+		if cell.FillColor != nil {
+			w.fill(cell.FillColor, w.x, y, w.width, h)
+		}
+		p.Engine.SetXY(w.x+left, y+textY)
+		p.drawLine(style, width, lineHeight, line, AlignLeft)
+		p.Engine.SetXY(w.x, y+h)
+	}
+	p.Engine.SetCellMargin(cm)
+}
+
+/*
+codeInsets returns the padding of code blocks, which is the cell margin of
+the engine on every side unless set by their style.
+*/
+func (w *markdownWriter) codeInsets() (top, left, right, bottom float64) {
+	if padding := w.style.CodeBlock.Padding; padding != nil {
+		return padding.Top, padding.Left, padding.Right, padding.Bottom
+	}
+	cm := w.pdf.Engine.GetCellMargin()
+	return cm, cm, cm, cm
+}
+
+func (w *markdownWriter) fill(color *RGB, x, y, width, height float64) {
+	e := w.pdf.Engine
+	r, g, b := e.GetFillColor()
+	e.SetFillColor(color.R, color.G, color.B)
+	e.Rect(x, y, width, height, "F")
+	e.SetFillColor(r, g, b)
+}
+
+/*
+quote writes the blocks of a block quote indented beside a bar.
+*/
+func (w *markdownWriter) quote(blocks []*mdBlock) error {
+	saved := *w
+	w.bars = append(slices.Clone(w.bars), w.x+w.barWidth()/2)
+	w.x += w.indent() / 2
+	w.width -= w.indent() / 2
+	if w.style.Quote != nil {
+		w.text = w.style.Quote
+	}
+	err := w.blocks(blocks, w.spacing())
+	*w = saved
+	w.pdf.Engine.SetX(w.x)
+	return err
+}
+
+/*
+list writes the items of a list indented after their markers: numbers for
+ordered lists, and bullets that change with the nesting of unordered lists.
+*/
+func (w *markdownWriter) list(list *mdBlock) error {
+	p := w.pdf
+	saved := *w
+	indent := w.indent()
+	lineHeight := p.lineHeight(w.text)
+	gap := lineHeight / 2
+	for i, item := range list.Children {
+		y := w.fit(lineHeight)
+		if list.Ordered {
+			cm := p.Engine.GetCellMargin()
+			p.Engine.SetCellMargin(0)
+			p.Engine.SetXY(saved.x, y)
+			p.drawLine(w.text, indent-gap, lineHeight, strconv.Itoa(list.Start+i)+".", AlignRight)
+			p.Engine.SetCellMargin(cm)
+		} else {
+			w.bullet(saved.x+indent-gap, y+lineHeight/2, saved.level)
+		}
+		p.Engine.SetXY(saved.x+indent, y)
+		if len(item.Children) == 0 {
+			w.drawBars(y, lineHeight)
+			p.Engine.SetY(y + lineHeight)
+		}
+		w.x, w.width, w.level = saved.x+indent, saved.width-indent, saved.level+1
+		if err := w.blocks(item.Children, 0); err != nil {
+			*w = saved
+			return err
+		}
+	}
+	*w = saved
+	p.Engine.SetX(w.x)
+	return p.Err()
+}
+
+/*
+bullet draws the bullet of an item of an unordered list, ending at x and
+centered on the x-height of the text around y: a disc at the first level of
+lists, a circle at the second and a square below.
+*/
+func (w *markdownWriter) bullet(x, y float64, level int) {
+	e := w.pdf.Engine
+	size := e.PointToUnitConvert(w.text.FontSize)
+	r := size * 0.18
+	x, y = x-r, y+size*0.05
+	color := w.text.FontColor
+	if color == nil {
+		color = NewRGB(0, 0, 0)
+	}
+	fr, fg, fb := e.GetFillColor()
+	dr, dg, db := e.GetDrawColor()
+	e.SetFillColor(color.R, color.G, color.B)
+	e.SetDrawColor(color.R, color.G, color.B)
+	switch level % 3 {
+	case 0:
+		e.Circle(x, y, r, "F")
+	case 1:
+		lineWidth := e.GetLineWidth()
+		e.SetLineWidth(r / 3)
+		e.Circle(x, y, r-r/6, "D")
+		e.SetLineWidth(lineWidth)
+	default:
+		e.Rect(x-r, y-r, 2*r, 2*r, "F")
+	}
+	e.SetFillColor(fr, fg, fb)
+	e.SetDrawColor(dr, dg, db)
+}
+
+/*
+table writes a pipe table with the table code, within the column.
+*/
+func (w *markdownWriter) table(b *mdBlock) error {
+	p := w.pdf
+	table := NewTable(&Padding{
+		Left:  w.x - p.PageMarginLeft,
+		Right: p.PageMarginLeft + p.PageBodyWidth - w.x - w.width,
+	})
+	table.SetAutoWidth(true)
+	for i, row := range b.Rows {
+		style := w.style.TableCell
+		if i == 0 {
+			style = w.style.TableHeader
+		}
+		var cells []*Cell
+		for col, text := range row {
+			cell := NewCell("", alignedStyle(style, b.Aligns[col]), 0, 0)
+			cell.SetParagraph(w.spans(parseInlines(text), cell.Style.FontStyle))
+			cells = append(cells, cell)
+		}
+		if i == 0 {
+			table.AddHeaderRow(cells...)
+		} else {
+			table.AddRow(cells...)
+		}
+	}
+	err := p.WriteTableRows(table)
+	p.Engine.SetX(w.x)
+	return err
+}
+
+/*
+image writes an image from a file at its own size, scaled down to the width
+of the column. An image that cannot be read is written as its alternative
+text, or its source if it has none, and its error is kept for WriteMarkdown.
+*/
+func (w *markdownWriter) image(src, alt string) {
+	p := w.pdf
+	img := NewCellImage(src, 0, 0)
+	if _, _, err := img.load(p); err != nil {
+		if p.Err() != nil {
+			return
+		}
+		w.imageErrs = append(w.imageErrs, err)
+		if alt == "" {
+			alt = src
+		}
+		w.paragraph(w.spans([]mdInline{{Text: alt}}, w.text), w.text, w.style.Align)
+		return
+	}
+	width, height := img.size(p, w.width)
+	if p.Err() != nil {
+		return
+	}
+	y := w.fit(height)
+	w.drawBars(y, height)
+	img.draw(p, w.x, y, width, height)
+	p.Engine.SetXY(w.x, y+height)
+}
+
+/*
+rule draws a thematic break across the column, in the middle of a line.
+*/
+func (w *markdownWriter) rule() {
+	p := w.pdf
+	lineHeight := p.lineHeight(w.text)
+	y := w.fit(lineHeight)
+	w.drawBars(y, lineHeight)
+	if color := w.style.RuleColor; color != nil {
+		r, g, b := p.Engine.GetDrawColor()
+		p.Engine.SetDrawColor(color.R, color.G, color.B)
+		p.Engine.Line(w.x, y+lineHeight/2, w.x+w.width, y+lineHeight/2)
+		p.Engine.SetDrawColor(r, g, b)
+	}
+	p.Engine.SetXY(w.x, y+lineHeight)
+}
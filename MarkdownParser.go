@@ -0,0 +1,644 @@
+package gopdf
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/*
+mdKind is the kind of a block of a Markdown document.
+*/
+type mdKind int
+
+const (
+	mdParagraph mdKind = iota
+	mdHeading
+	mdCode
+	mdQuote
+	mdList
+	mdItem
+	mdTable
+	mdRule
+)
+
+/*
+mdBlock is a block of a Markdown document. Paragraphs and headings hold
+their inline text, code blocks their lines, quotes and list items the
+blocks they contain, lists their items and tables their rows of cells,
+the first of which is the header.
+*/
+type mdBlock struct {
+	Kind     mdKind
+	Level    int
+	Text     string
+	Ordered  bool
+	Start    int
+	Children []*mdBlock
+	Rows     [][]string
+	Aligns   []string
+}
+
+var (
+	mdATXHeading   = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?[ \t]*$`)
+	mdClosingHash  = regexp.MustCompile(`(?:^|[ \t]+)#+$`)
+	mdFence        = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})(.*)$")
+	mdRuleLine     = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	mdSetextLine   = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	mdBullet       = regexp.MustCompile(`^( {0,3})([-+*])([ \t]+|$)`)
+	mdOrdered      = regexp.MustCompile(`^( {0,3})(\d{1,9})([.)])([ \t]+|$)`)
+	mdDelimiterRow = regexp.MustCompile(`^ {0,3}\|?[ \t]*:?-+:?[ \t]*(?:\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+)
+
+/*
+parseMarkdown parses the blocks of a Markdown document.
+*/
+func parseMarkdown(text string) []*mdBlock {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\t", "    ")
+	return parseBlocks(strings.Split(text, "\n"))
+}
+
+func parseBlocks(lines []string) []*mdBlock {
+	var blocks []*mdBlock
+	var para []string
+	flush := func() {
+		if len(para) > 0 {
+			blocks = append(blocks, &mdBlock{Kind: mdParagraph, Text: strings.Join(para, "\n")})
+			para = nil
+		}
+	}
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if isBlank(line) {
+			flush()
+			continue
+		}
+		if m := mdSetextLine.FindStringSubmatch(line); m != nil && para != nil {
+			level := 1
+			if m[1][0] == '-' {
+				level = 2
+			}
+			blocks = append(blocks, &mdBlock{Kind: mdHeading, Level: level, Text: strings.Join(para, "\n")})
+			para = nil
+			continue
+		}
+		if mdRuleLine.MatchString(line) {
+			flush()
+			blocks = append(blocks, &mdBlock{Kind: mdRule})
+			continue
+		}
+		if m := mdATXHeading.FindStringSubmatch(line); m != nil {
+			flush()
+			text := strings.TrimSpace(mdClosingHash.ReplaceAllString(m[2], ""))
+			blocks = append(blocks, &mdBlock{Kind: mdHeading, Level: len(m[1]), Text: text})
+			continue
+		}
+		if m := mdFence.FindStringSubmatch(line); m != nil && !(m[2][0] == '`' && strings.Contains(m[3], "`")) {
+			flush()
+			var block *mdBlock
+			block, i = parseFence(lines, i, len(m[1]), m[2])
+			blocks = append(blocks, block)
+			continue
+		}
+		if _, ok := quoteLine(line); ok {
+			flush()
+			var block *mdBlock
+			block, i = parseQuote(lines, i)
+			blocks = append(blocks, block)
+			continue
+		}
+		if m, ok := listMarker(line); ok && (para == nil || !m.ordered || m.start == 1) {
+			flush()
+			var block *mdBlock
+			block, i = parseList(lines, i)
+			blocks = append(blocks, block)
+			continue
+		}
+		if para == nil && indentOf(line) >= 4 {
+			var block *mdBlock
+			block, i = parseIndentedCode(lines, i)
+			blocks = append(blocks, block)
+			continue
+		}
+		if i+1 < len(lines) && strings.Contains(line, "|") && mdDelimiterRow.MatchString(lines[i+1]) {
+			if header := splitTableRow(line); len(header) == len(splitTableRow(lines[i+1])) {
+				flush()
+				var block *mdBlock
+				block, i = parseTable(lines, i)
+				blocks = append(blocks, block)
+				continue
+			}
+		}
+		para = append(para, strings.TrimLeft(line, " "))
+	}
+	flush()
+	return blocks
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+/*
+startsBlock reports whether a line starts a block other than a paragraph,
+and so ends the lazy continuation of a quote or list item.
+*/
+func startsBlock(line string) bool {
+	if isBlank(line) || mdRuleLine.MatchString(line) || mdATXHeading.MatchString(line) || mdFence.MatchString(line) {
+		return true
+	}
+	if _, ok := quoteLine(line); ok {
+		return true
+	}
+	_, ok := listMarker(line)
+	return ok
+}
+
+/*
+parseFence parses a fenced code block starting at line i, and returns it
+with the index of its last line.
+*/
+func parseFence(lines []string, i, indent int, fence string) (*mdBlock, int) {
+	var code []string
+	for i++; i < len(lines); i++ {
+		line := lines[i]
+		if trimmed := strings.TrimSpace(line); indentOf(line) < 4 && strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			break
+		}
+		code = append(code, line[min(indent, indentOf(line)):])
+	}
+	return &mdBlock{Kind: mdCode, Text: strings.Join(code, "\n")}, i
+}
+
+func parseIndentedCode(lines []string, i int) (*mdBlock, int) {
+	var code []string
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if !isBlank(line) && indentOf(line) < 4 {
+			break
+		}
+		code = append(code, line[min(4, len(line)):])
+	}
+	for len(code) > 0 && isBlank(code[len(code)-1]) {
+		code = code[:len(code)-1]
+	}
+	return &mdBlock{Kind: mdCode, Text: strings.Join(code, "\n")}, i - 1
+}
+
+/*
+quoteLine returns a line of a block quote without its marker.
+*/
+func quoteLine(line string) (string, bool) {
+	rest := strings.TrimLeft(line, " ")
+	if len(line)-len(rest) > 3 || !strings.HasPrefix(rest, ">") {
+		return "", false
+	}
+	rest = rest[1:]
+	return strings.TrimPrefix(rest, " "), true
+}
+
+func parseQuote(lines []string, i int) (*mdBlock, int) {
+	var quoted []string
+	for ; i < len(lines) && !isBlank(lines[i]); i++ {
+		if rest, ok := quoteLine(lines[i]); ok {
+			quoted = append(quoted, rest)
+		} else if !startsBlock(lines[i]) && !isBlank(quoted[len(quoted)-1]) {
+			// A lazy continuation of the quoted paragraph.
+			quoted = append(quoted, lines[i])
+		} else {
+			break
+		}
+	}
+	return &mdBlock{Kind: mdQuote, Children: parseBlocks(quoted)}, i - 1
+}
+
+/*
+mdMarker is the marker of a list item, and the indent of its content.
+*/
+type mdMarker struct {
+	ordered bool
+	start   int
+	char    byte
+	indent  int
+}
+
+func listMarker(line string) (mdMarker, bool) {
+	if mdRuleLine.MatchString(line) {
+		return mdMarker{}, false
+	}
+	var m mdMarker
+	var match []string
+	if match = mdBullet.FindStringSubmatch(line); match != nil {
+		m.char = match[2][0]
+	} else if match = mdOrdered.FindStringSubmatch(line); match != nil {
+		m.ordered = true
+		m.start, _ = strconv.Atoi(match[2])
+		m.char = match[3][0]
+	} else {
+		return mdMarker{}, false
+	}
+	m.indent = len(match[0])
+	spaces := len(match[len(match)-1])
+	if spaces == 0 || spaces > 4 || isBlank(line[len(match[0]):]) {
+		// The content starts one space after the marker.
+		m.indent = len(match[0]) - spaces + 1
+	}
+	return m, true
+}
+
+/*
+parseList parses the items of a list starting at line i, which share the
+kind of their markers.
+*/
+func parseList(lines []string, i int) (*mdBlock, int) {
+	first, _ := listMarker(lines[i])
+	list := &mdBlock{Kind: mdList, Ordered: first.ordered, Start: first.start}
+	for i < len(lines) {
+		m, ok := listMarker(lines[i])
+		if !ok || m.ordered != first.ordered || m.char != first.char {
+			break
+		}
+		item := []string{contentAt(lines[i], m.indent)}
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			switch {
+			case isBlank(line):
+				item = append(item, "")
+				continue
+			case indentOf(line) >= m.indent:
+				item = append(item, line[m.indent:])
+				continue
+			case !isBlank(item[len(item)-1]) && !startsBlock(line):
+				// A lazy continuation of the paragraph of the item.
+				item = append(item, strings.TrimLeft(line, " "))
+				continue
+			}
+			break
+		}
+		list.Children = append(list.Children, &mdBlock{Kind: mdItem, Children: parseBlocks(item)})
+	}
+	return list, i - 1
+}
+
+func contentAt(line string, indent int) string {
+	if indent >= len(line) {
+		return ""
+	}
+	return line[indent:]
+}
+
+/*
+parseTable parses a pipe table starting at its header row at line i.
+*/
+func parseTable(lines []string, i int) (*mdBlock, int) {
+	header := splitTableRow(lines[i])
+	table := &mdBlock{Kind: mdTable, Rows: [][]string{header}}
+	for _, cell := range splitTableRow(lines[i+1]) {
+		left, right := strings.HasPrefix(cell, ":"), strings.HasSuffix(cell, ":")
+		switch {
+		case left && right:
+			table.Aligns = append(table.Aligns, AlignCenter)
+		case right:
+			table.Aligns = append(table.Aligns, AlignRight)
+		case left:
+			table.Aligns = append(table.Aligns, AlignLeft)
+		default:
+			table.Aligns = append(table.Aligns, "")
+		}
+	}
+	for i += 2; i < len(lines) && !isBlank(lines[i]) && !startsBlock(lines[i]); i++ {
+		row := splitTableRow(lines[i])
+		// Rows have as many cells as the header.
+		for len(row) < len(header) {
+			row = append(row, "")
+		}
+		table.Rows = append(table.Rows, row[:len(header)])
+	}
+	return table, i - 1
+}
+
+/*
+splitTableRow splits a row of a pipe table into its cells, at the pipes
+not escaped by a backslash.
+*/
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+/*
+mdInline is a piece of inline text of a Markdown block in a single style.
+Images hold their source in Image and their alternative text in Text.
+*/
+type mdInline struct {
+	Text   string
+	Bold   bool
+	Italic bool
+	Strike bool
+	Code   bool
+	Link   string
+	Image  string
+}
+
+var mdAutolink = regexp.MustCompile(`^<([a-zA-Z][a-zA-Z0-9+.-]{1,31}:[^\s<>]*|[a-zA-Z0-9.!#$%&'*+/=?^_{|}~-]+@[a-zA-Z0-9.-]+)>`)
+
+/*
+parseInlines parses the inline text of a paragraph, heading or table cell.
+Soft line breaks become spaces, and hard line breaks, made by two spaces or
+a backslash at the end of a line, newlines.
+*/
+func parseInlines(text string) []mdInline {
+	var out []mdInline
+	parseInline(strings.TrimSpace(text), mdInline{}, &out)
+	return out
+}
+
+func parseInline(s string, st mdInline, out *[]mdInline) {
+	var buf strings.Builder
+	emit := func() {
+		if buf.Len() > 0 {
+			in := st
+			in.Text = buf.String()
+			*out = append(*out, in)
+			buf.Reset()
+		}
+	}
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && s[i+1] == '\n':
+			buf.WriteByte('\n')
+			i += 2
+			continue
+		case c == '\\' && i+1 < len(s) && strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", s[i+1]) >= 0:
+			buf.WriteByte(s[i+1])
+			i += 2
+			continue
+		case c == '`':
+			n := runLength(s, i)
+			if end := closingBackticks(s, i+n, n); end >= 0 {
+				emit()
+				code := strings.ReplaceAll(s[i+n:end], "\n", " ")
+				if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+					code = code[1 : len(code)-1]
+				}
+				in := st
+				in.Text, in.Code = code, true
+				*out = append(*out, in)
+				i = end + n
+			} else {
+				buf.WriteString(s[i : i+n])
+				i += n
+			}
+			continue
+		case c == '!' && i+1 < len(s) && s[i+1] == '[':
+			if label, dest, end, ok := parseLink(s, i+1); ok {
+				emit()
+				in := st
+				in.Text, in.Image = plainText(label), dest
+				*out = append(*out, in)
+				i = end
+				continue
+			}
+		case c == '[':
+			if label, dest, end, ok := parseLink(s, i); ok {
+				emit()
+				link := st
+				link.Link = dest
+				parseInline(label, link, out)
+				i = end
+				continue
+			}
+		case c == '<':
+			if m := mdAutolink.FindStringSubmatch(s[i:]); m != nil {
+				emit()
+				in := st
+				in.Text, in.Link = m[1], m[1]
+				if !strings.Contains(m[1], ":") {
+					in.Link = "mailto:" + m[1]
+				}
+				*out = append(*out, in)
+				i += len(m[0])
+				continue
+			}
+		case c == '*' || c == '_' || c == '~':
+			n := runLength(s, i)
+			if size, end := emphasis(s, i, n); end >= 0 {
+				emit()
+				inner := st
+				switch {
+				case c == '~':
+					inner.Strike = true
+				case size == 3:
+					inner.Bold, inner.Italic = true, true
+				case size == 2:
+					inner.Bold = true
+				default:
+					inner.Italic = true
+				}
+				parseInline(s[i+size:end], inner, out)
+				i = end + size
+			} else {
+				buf.WriteString(s[i : i+n])
+				i += n
+			}
+			continue
+		case c == '\n':
+			text := buf.String()
+			trimmed := strings.TrimRight(text, " ")
+			buf.Reset()
+			buf.WriteString(trimmed)
+			if len(text)-len(trimmed) >= 2 {
+				buf.WriteByte('\n')
+			} else {
+				buf.WriteByte(' ')
+			}
+			for i++; i < len(s) && s[i] == ' '; i++ {
+			}
+			continue
+		}
+		buf.WriteByte(c)
+		i++
+	}
+	emit()
+}
+
+func runLength(s string, i int) int {
+	n := 1
+	for i+n < len(s) && s[i+n] == s[i] {
+		n++
+	}
+	return n
+}
+
+func closingBackticks(s string, from, n int) int {
+	for i := from; i < len(s); {
+		if s[i] != '`' {
+			i++
+			continue
+		}
+		run := runLength(s, i)
+		if run == n {
+			return i
+		}
+		i += run
+	}
+	return -1
+}
+
+/*
+emphasis returns the number of delimiters of the run of n at i that open
+emphasis, strikethrough for two tildes, and the index of the run closing
+it, or -1.
+*/
+func emphasis(s string, i, n int) (int, int) {
+	if !canOpen(s, i, n) {
+		return 0, -1
+	}
+	for _, size := range []int{3, 2, 1} {
+		if size > n || s[i] == '~' && size != 2 {
+			continue
+		}
+		if end := closingDelimiter(s, i+size, s[i], size); end >= 0 {
+			return size, end
+		}
+	}
+	return 0, -1
+}
+
+/*
+canOpen reports whether a run of n emphasis delimiters at i can open
+emphasis: it is followed by text, and underscores are not inside a word.
+*/
+func canOpen(s string, i, n int) bool {
+	if i+n >= len(s) || s[i+n] == ' ' || s[i+n] == '\n' {
+		return false
+	}
+	return s[i] != '_' || i == 0 || !isWordByte(s[i-1])
+}
+
+/*
+closingDelimiter returns the index of the run of size delimiters closing
+emphasis opened before from, or -1. Runs of other sizes are left to nested
+emphasis, and code spans are skipped.
+*/
+func closingDelimiter(s string, from int, c byte, size int) int {
+	for i := from; i < len(s); {
+		switch s[i] {
+		case '`':
+			n := runLength(s, i)
+			if end := closingBackticks(s, i+n, n); end >= 0 {
+				i = end + n
+			} else {
+				i += n
+			}
+			continue
+		case '\\':
+			i += 2
+			continue
+		case c:
+			n := runLength(s, i)
+			closes := i > from && s[i-1] != ' ' && s[i-1] != '\n'
+			if c == '_' && i+n < len(s) && isWordByte(s[i+n]) {
+				closes = false
+			}
+			if closes && (n == size || size < 3 && n >= 3) {
+				return i + n - size
+			}
+			i += n
+			continue
+		}
+		i++
+	}
+	return -1
+}
+
+func isWordByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || b >= 0x80
+}
+
+/*
+parseLink parses a link at the bracket at i, as in [label](destination
+"title"), and returns its label, its destination and the index after it.
+*/
+func parseLink(s string, i int) (label, dest string, end int, ok bool) {
+	depth := 0
+	j := i
+	for ; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+			continue
+		case '`':
+			n := runLength(s, j)
+			if k := closingBackticks(s, j+n, n); k >= 0 {
+				j = k + n - 1
+			} else {
+				j += n - 1
+			}
+			continue
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+		if depth == 0 {
+			break
+		}
+	}
+	if j+1 >= len(s) || s[j+1] != '(' {
+		return "", "", 0, false
+	}
+	label = s[i+1 : j]
+	paren := strings.IndexByte(s[j+2:], ')')
+	if paren < 0 {
+		return "", "", 0, false
+	}
+	end = j + 3 + paren
+	target := strings.TrimSpace(s[j+2 : end-1])
+	if strings.HasPrefix(target, "<") {
+		if k := strings.IndexByte(target, '>'); k > 0 {
+			return label, target[1:k], end, true
+		}
+	}
+	// Drop the title of the link.
+	if k := strings.IndexAny(target, " \n"); k >= 0 {
+		target = target[:k]
+	}
+	return label, target, end, true
+}
+
+/*
+plainText returns the text of inline Markdown without its markup, as used
+for the alternative text of images.
+*/
+func plainText(s string) string {
+	var b strings.Builder
+	for _, in := range parseInlines(s) {
+		b.WriteString(in.Text)
+	}
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package gopdf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMarkdownBlocks(t *testing.T) {
+	blocks := parseMarkdown("Title\n=====\n\n## Sub ##\n\ntext\nwrapped\n\n    code\n\n* a\n* b\n\n5) five\n\n***")
+	var kinds []mdKind
+	for _, b := range blocks {
+		kinds = append(kinds, b.Kind)
+	}
+	want := []mdKind{mdHeading, mdHeading, mdParagraph, mdCode, mdList, mdList, mdRule}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Fatalf("kinds = %v, want %v", kinds, want)
+	}
+	if blocks[0].Level != 1 || blocks[0].Text != "Title" {
+		t.Errorf("setext heading = %d %q", blocks[0].Level, blocks[0].Text)
+	}
+	if blocks[1].Level != 2 || blocks[1].Text != "Sub" {
+		t.Errorf("ATX heading = %d %q", blocks[1].Level, blocks[1].Text)
+	}
+	if len(blocks[4].Children) != 2 || blocks[4].Ordered {
+		t.Errorf("bullet list = %+v", blocks[4])
+	}
+	if !blocks[5].Ordered || blocks[5].Start != 5 {
+		t.Errorf("ordered list = %+v", blocks[5])
+	}
+}
+
+func TestParseMarkdownTable(t *testing.T) {
+	blocks := parseMarkdown("| a | b |\n|:--|--:|\n| 1 | 2 \\| 3 |")
+	if len(blocks) != 1 || blocks[0].Kind != mdTable {
+		t.Fatalf("blocks = %+v", blocks)
+	}
+	if want := [][]string{{"a", "b"}, {"1", "2 | 3"}}; !reflect.DeepEqual(blocks[0].Rows, want) {
+		t.Errorf("rows = %q, want %q", blocks[0].Rows, want)
+	}
+	if want := []string{AlignLeft, AlignRight}; !reflect.DeepEqual(blocks[0].Aligns, want) {
+		t.Errorf("aligns = %q, want %q", blocks[0].Aligns, want)
+	}
+}
+
+func TestParseInlines(t *testing.T) {
+	got := parseInlines("plain **bold** *it* ~~gone~~ `x` [link](https://example.com) ![alt](a.png)")
+	want := []mdInline{
+		{Text: "plain "},
+		{Text: "bold", Bold: true},
+		{Text: " "},
+		{Text: "it", Italic: true},
+		{Text: " "},
+		{Text: "gone", Strike: true},
+		{Text: " "},
+		{Text: "x", Code: true},
+		{Text: " "},
+		{Text: "link", Link: "https://example.com"},
+		{Text: " "},
+		{Text: "alt", Image: "a.png"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseInlines =\n%+v\nwant\n%+v", got, want)
+	}
+}
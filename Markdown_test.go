@@ -0,0 +1,94 @@
+package gopdf
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMarkdownBlocks(t *testing.T) {
+	p := newTestPDF()
+	doc := "# Title\n\nSome **bold** text.\n\n- one\n- two\n\n> quoted\n\n```\ncode line\n```\n\n| A | B |\n|---|---|\n| 1 | 2 |\n\n---\n\nend"
+	if err := p.WriteMarkdown(doc, nil); err != nil {
+		t.Fatalf("WriteMarkdown: %v", err)
+	}
+	content := pageOutput(t, p)[0]
+	order := []string{"Title", "bold", "one", "two", "quoted", "code line", "A", "1", "end"}
+	last := findText(t, content, order[0])
+	for _, s := range order[1:] {
+		d := findText(t, content, s)
+		if d.Y > last.Y {
+			t.Errorf("%q at y %.2f is above %q at y %.2f", s, d.Y, last.Text, last.Y)
+		}
+		last = d
+	}
+	if one, quoted := findText(t, content, "one"), findText(t, content, "quoted"); one.X <= p.PageMarginLeft || quoted.X <= p.PageMarginLeft {
+		t.Errorf("list at x %.2f and quote at x %.2f are not indented", one.X, quoted.X)
+	}
+	if lastFont(before(t, content, "Title")) == lastFont(before(t, content, "end")) {
+		t.Errorf("heading is drawn in the font of the text")
+	}
+}
+
+func TestMarkdownOrderedListStart(t *testing.T) {
+	p := newTestPDF()
+	if err := p.WriteMarkdown("3. three\n4. four", nil); err != nil {
+		t.Fatalf("WriteMarkdown: %v", err)
+	}
+	content := pageOutput(t, p)[0]
+	findText(t, content, "3.")
+	findText(t, content, "4.")
+}
+
+func TestMarkdownImage(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "image.png")
+	if err := os.WriteFile(src, testPNG(t, 20, 10), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p := newTestPDF()
+	if err := p.WriteMarkdown("![alt]("+src+")\n\nafter", nil); err != nil {
+		t.Fatalf("WriteMarkdown: %v", err)
+	}
+	content := pageOutput(t, p)[0]
+	if !strings.Contains(content, " Do") {
+		t.Errorf("image not drawn:\n%s", content)
+	}
+	if strings.Contains(content, "(alt)Tj") {
+		t.Errorf("alternative text drawn for a readable image")
+	}
+	findText(t, content, "after")
+}
+
+func TestMarkdownMissingImage(t *testing.T) {
+	p := newTestPDF()
+	err := p.WriteMarkdown("![a missing chart](missing.png)\n\n![](gone.png)\n\nafter", nil)
+	var imageErr *ImageError
+	if !errors.As(err, &imageErr) || imageErr.Name != "missing.png" {
+		t.Fatalf("WriteMarkdown returned %v, want an ImageError for missing.png", err)
+	}
+	if !strings.Contains(err.Error(), "gone.png") {
+		t.Errorf("error %v does not report gone.png", err)
+	}
+	if err := p.Err(); err != nil {
+		t.Fatalf("document failed: %v", err)
+	}
+	content := pageOutput(t, p)[0]
+	findText(t, content, "a missing chart")
+	findText(t, content, "gone.png")
+	findText(t, content, "after")
+}
+
+func TestMarkdownTallTable(t *testing.T) {
+	p := newTestPDF()
+	doc := "| A | B |\n|---|---|\n| 1 | " + strings.Repeat("word ", 3000) + "|\n\nafter"
+	if err := p.WriteMarkdown(doc, nil); err != nil {
+		t.Fatalf("WriteMarkdown: %v", err)
+	}
+	pages := pageOutput(t, p)
+	if len(pages) < 2 {
+		t.Fatalf("table drawn on %d pages, want it split across pages", len(pages))
+	}
+	findText(t, pages[len(pages)-1], "after")
+}
@@ -1,12 +1,13 @@
 package gopdf
 
 import (
-	"bufio"
 	"bytes"
-	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"strings"
 
-	"github.com/METADIV-GO/gopdf/ttf_bytes"
 	"github.com/METADIV-GO/nanoid"
 	"github.com/jung-kurt/gofpdf"
 )
@@ -16,9 +17,7 @@ func New(layout ...*PageLayout) *PDF {
 	pdf.CurrentPageIndex = -1
 	pdf.PageLayout = pdf.processLayoutOpts(layout...)
 	pdf.initEngine(pdf.PageLayout)
-	pdf.initDefaultSupportingFonts()
 	pdf.initDefaultFontStyle()
-	pdf.initPageBodySize()
 	pdf.Engine.AddPage()
 	return pdf
 }
@@ -29,183 +28,366 @@ type PDF struct {
 	DefaultFontStyle *FontStyle   `json:"default_font_style"`
 	CurrentPageIndex int          `json:"-"`
 
+	err       error
+	fontKey   string
+	fontStyle *FontStyle // copy of the font style set up last, with fontKey
+
+	headers      pageRegions
+	footers      pageRegions
+	margin       PageMargin // margins of the document layout
+	marginBottom float64
+	headerHeight float64     // height of the header of the current page
+	footerHeight float64     // height of the footer of the current page
+	frames       []pageFrame // geometry of each page
+	sections     []pageSection
+	watermarks   []*Watermark
+	pageCount    int         // number of pages, once the document is finished
+	slice        *blockSlice // slice of a block split across pages being drawn
+	inRegion     bool        // drawing headers, footers and watermarks, which never break the page
+
 	PageHeight       float64 `json:"page_height"`
 	PageWidth        float64 `json:"page_width"`
 	PageMarginTop    float64 `json:"page_margin_top"`
 	PageMarginBottom float64 `json:"page_margin_bottom"`
 	PageMarginLeft   float64 `json:"page_margin_left"`
 	PageMarginRight  float64 `json:"page_margin_right"`
-	PageBodyHeight   float64 `json:"page_body_height"` // Page height minus top and bottom margins
+	PageBodyHeight   float64 `json:"page_body_height"` // Page height minus top and bottom margins, header and footer
 	PageBodyWidth    float64 `json:"page_body_width"`  // Page width minus left and right margins
 }
 
+/*
+AddPage adds a page with the layout of the document.
+*/
 func (p *PDF) AddPage() {
+	p.setMargins(p.layoutMargin(p.PageLayout))
 	p.Engine.AddPage()
-	p.initPageBodySize()
+}
+
+/*
+AddPageWithLayout adds a page with its own paper, orientation and margins,
+such as a landscape page in a portrait document. Pages the content flows on
+to keep the layout, until the next AddPage.
+*/
+func (p *PDF) AddPageWithLayout(layout *PageLayout) {
+	if layout == nil {
+		p.AddPage()
+		return
+	}
+	p.setMargins(p.layoutMargin(layout))
+	p.Engine.AddPageFormat(layout.pageFormat(p.PageLayout.unit()))
+}
+
+/*
+continuePage adds a page with the layout of the current page, for content
+flowing on to the next page.
+*/
+func (p *PDF) continuePage() {
+	p.Engine.AddPageFormat(pageFormat(p.PageWidth, p.PageHeight))
 }
 
 func (p *PDF) SetDefaultFontStyle(style *FontStyle) {
 	p.DefaultFontStyle = style
 }
 
-func (p *PDF) WriteText(text string, style *FontStyle) {
+func (p *PDF) WriteText(text string, style *FontStyle) error {
+	if err := p.Err(); err != nil {
+		return err
+	}
 	if style == nil {
 		style = p.DefaultFontStyle
 	}
 	style.Setup(p)
-	p.Engine.Write(style.LineHeight, text)
+	if err := p.Err(); err != nil {
+		return err
+	}
+	text = p.resolvePlaceholders(text)
+	// The engine would wrap page counts at the width of their aliases.
+	if style.needsLineLayout() || p.layoutText(text) != text {
+		p.writeFlow(style, text)
+	} else {
+		p.Engine.Write(p.lineHeight(style), text)
+	}
+	return p.Err()
 }
 
-func (p *PDF) WriteLink(text string, link string, style *FontStyle) {
+func (p *PDF) WriteLink(text string, link string, style *FontStyle) error {
+	if err := p.Err(); err != nil {
+		return err
+	}
 	if style == nil {
 		style = p.DefaultFontStyle
 	}
-	p.Engine.WriteLinkString(style.LineHeight, text, link)
+	p.Engine.WriteLinkString(p.lineHeight(style), p.resolvePlaceholders(text), link)
+	return p.Err()
 }
 
-func (p *PDF) WriteTextBox(text string, align string, style *FontStyle) {
+func (p *PDF) WriteTextBox(text string, align string, style *FontStyle) error {
+	if err := p.Err(); err != nil {
+		return err
+	}
 	if style == nil {
 		style = p.DefaultFontStyle
 	}
 	style.Setup(p)
-	lines := strings.Split(text, "\n")
+	if err := p.Err(); err != nil {
+		return err
+	}
+	lines := strings.Split(p.resolvePlaceholders(text), "\n")
 	for i := range lines {
-		p.Engine.WriteAligned(0, style.LineHeight, lines[i], p.processHAlign(align))
+		if style.needsLineLayout() || align == AlignJustify || p.layoutText(lines[i]) != lines[i] {
+			p.writeBox(style, lines[i], align)
+		} else {
+			p.Engine.WriteAligned(0, p.lineHeight(style), lines[i], p.processHAlign(align))
+		}
 		p.LineBreak(style)
 	}
+	return p.Err()
 }
 
-func (p *PDF) WriteImage(imgSrc string, width float64, height float64, flow bool) {
+func (p *PDF) WriteImage(imgSrc string, width float64, height float64, flow bool) error {
+	if err := p.Err(); err != nil {
+		return err
+	}
 	if width == 0 {
 		width = p.PageBodyWidth
 	}
 	p.Engine.Image(imgSrc, p.PageMarginLeft, p.Engine.GetY(), width, height, flow, "", 0, "")
+	if p.Engine.Err() {
+		p.setError(&ImageError{Name: imgSrc, Err: p.Engine.Error()})
+	}
+	return p.Err()
 }
 
-func (p *PDF) WriteImageBytes(imgBytes []byte, width float64, height float64, flow bool) {
+func (p *PDF) WriteImageBytes(imgBytes []byte, width float64, height float64, flow bool) error {
+	if err := p.Err(); err != nil {
+		return err
+	}
 	if width == 0 {
 		width = p.PageBodyWidth
 	}
 	name := nanoid.NewSafe()
 	p.Engine.RegisterImageOptionsReader(name, gofpdf.ImageOptions{ImageType: "png"}, bytes.NewReader(imgBytes))
+	if p.Engine.Err() {
+		p.setError(&ImageError{Name: name, Err: p.Engine.Error()})
+		return p.Err()
+	}
 	p.Engine.Image(name, p.PageMarginLeft, p.Engine.GetY(), width, height, flow, "", 0, "")
+	return p.Err()
 }
 
-func (p *PDF) WriteTable(cells []*Cell, padding *Padding) {
-
+/*
+WriteTable writes a single row of cells. Every cell is drawn to the height of
+the tallest one, with its text aligned within it. A row that does not fit
+on the page is moved to the next one. The position is left on the last line
+of the row, so that LineBreak moves below it.
+Use WriteTableRows for tables of several rows.
+*/
+func (p *PDF) WriteTable(cells []*Cell, padding *Padding) error {
+	if err := p.Err(); err != nil {
+		return err
+	}
+	x, width := p.PageMarginLeft, p.PageBodyWidth
 	if padding != nil {
 		if padding.Top > 0 {
 			p.Engine.Ln(padding.Top)
 		}
-		if padding.Left > 0 {
-			p.Engine.SetX(p.PageMarginLeft + padding.Left)
+		x += padding.Left
+		width -= padding.Left + padding.Right
+	}
+	row := [][]*Cell{cells}
+	columns := tableColumns(row, width)
+	block, _ := placeCells(row, len(columns))
+	p.measureBlock(block, columns)
+	if p.Engine.GetY()+block.height() > p.pageBreakTrigger() && p.Engine.GetY() > p.pageTop() && !p.inRegion {
+		p.continuePage()
+	}
+	p.drawBlock(block, x, columns)
+	// Step back by the line height of the tallest cell.
+	var cellHeight, lineHeight float64
+	for _, c := range block.cells {
+		if h := p.blockCellHeight(block, c, columns); h > cellHeight {
+			cellHeight, lineHeight = h, p.lineHeight(c.Cell.Style.FontStyle)
 		}
 	}
-
-	pageBodyWidth := p.PageBodyWidth
-	if padding != nil && padding.Right > 0 {
-		pageBodyWidth -= padding.Right
+	p.Engine.SetY(p.Engine.GetY() - lineHeight)
+	if padding != nil && padding.Bottom > 0 {
+		p.Engine.Ln(padding.Bottom)
 	}
-	if padding != nil && padding.Left > 0 {
-		pageBodyWidth -= padding.Left
+	return p.Err()
+}
+
+func (p *PDF) LineBreak(style *FontStyle) {
+	if style == nil {
+		style = p.DefaultFontStyle
 	}
+	p.Engine.Ln(p.lineHeight(style))
+}
 
-	var emptyWidth int
-	var usedWidth float64
-	for i := range cells {
-		if cells[i].Width == 0 && cells[i].WidthPercent == 0 {
-			emptyWidth++
-		} else if cells[i].WidthPercent > 0 && cells[i].Width == 0 {
-			cells[i].Width = pageBodyWidth * cells[i].WidthPercent
-			usedWidth += cells[i].Width
-		} else {
-			usedWidth += cells[i].Width
-		}
+/*
+Err returns the first error recorded while building the document, or nil.
+Once an error is recorded, the document stops accepting content and every
+Write method and output method returns the same error.
+*/
+func (p *PDF) Err() error {
+	if p.err != nil {
+		return p.err
+	}
+	if p.Engine.Err() {
+		return &EngineError{Err: p.Engine.Error()}
 	}
+	return nil
+}
 
-	widthOfCell := (pageBodyWidth - usedWidth) / float64(emptyWidth)
-	for i := range cells {
-		if cells[i].Width == 0 {
-			cells[i].Width = widthOfCell
-		}
+/*
+ToFile writes the document to the given file path. The document is written
+to a temporary file in the same directory first, which replaces the file
+once complete, so that a document that cannot be written leaves any file
+already at the path as it was.
+*/
+func (p *PDF) ToFile(filePath string) error {
+	if err := p.Err(); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(filePath), "."+filepath.Base(filePath)+".*")
+	if err != nil {
+		return err
 	}
+	// Temporary files are only readable by their owner.
+	mode := fs.FileMode(0o644)
+	if info, err := os.Stat(filePath); err == nil {
+		mode = info.Mode().Perm()
+	}
+	_, err = p.WriteTo(f)
+	if err == nil {
+		err = f.Chmod(mode)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), filePath)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
 
-	var countLineBreak int
-	for i := range cells {
-		cells[i].Text = strings.TrimSpace(cells[i].Text)
-		cells[i].Text = strings.TrimRight(cells[i].Text, "\n")
-		count := strings.Count(cells[i].Text, "\n")
-		if count > countLineBreak {
-			countLineBreak = count
-		}
+/*
+ToBytes returns the document as bytes, or nil if the document has an error.
+Use Bytes to inspect the error.
+*/
+func (p *PDF) ToBytes() []byte {
+	b, err := p.Bytes()
+	if err != nil {
+		return nil
 	}
+	return b
+}
 
-	for i := range cells {
-		count := strings.Count(cells[i].Text, "\n")
-		for j := 0; j < countLineBreak-count; j++ {
-			cells[i].Text += "\n "
-		}
+/*
+Bytes returns the document as bytes.
+*/
+func (p *PDF) Bytes() ([]byte, error) {
+	var b bytes.Buffer
+	if _, err := p.WriteTo(&b); err != nil {
+		return nil, err
 	}
+	return b.Bytes(), nil
+}
 
-	y := p.Engine.GetY()
-	maxY := y
-	for _, cell := range cells {
-		style := cell.Style
-		style.FontStyle.Setup(p)
-		style.SetupFillColor(p)
-		style.BorderStyle.SetupBorderColor(p)
-		x := p.Engine.GetX()
-		p.Engine.MultiCell(
-			cell.Width,
-			style.FontStyle.LineHeight,
-			cell.Text,
-			cell.Style.BorderStyle.BorderToEngineString(),
-			cell.Style.ToAlignEngineString(),
-			cell.Style.FillColor != nil)
-		if p.Engine.GetY() > maxY {
-			maxY = p.Engine.GetY() - style.FontStyle.LineHeight
+/*
+WriteTo writes the document to w and returns the number of bytes written.
+It implements io.WriterTo. Like the other output methods, it closes the
+document, so it should be called once.
+*/
+func (p *PDF) WriteTo(w io.Writer) (int64, error) {
+	if err := p.Err(); err != nil {
+		return 0, err
+	}
+	p.finish()
+	if err := p.Err(); err != nil {
+		return 0, err
+	}
+	cw := &countWriter{w: w}
+	if len(p.sections) == 0 {
+		if err := p.Engine.Output(cw); err != nil {
+			return cw.n, p.outputError(err)
 		}
-		p.Engine.SetY(y)
-		p.Engine.SetX(x + cell.Width)
+		return cw.n, nil
 	}
-	p.Engine.SetX(p.PageMarginLeft)
-	p.Engine.SetY(maxY)
-	if padding != nil && padding.Bottom > 0 {
-		p.Engine.Ln(padding.Bottom)
+	var b bytes.Buffer
+	if err := p.Engine.Output(&b); err != nil {
+		return 0, p.outputError(err)
+	}
+	doc, err := p.appendPageLabels(b.Bytes())
+	if err != nil {
+		return 0, err
 	}
+	_, err = cw.Write(doc)
+	return cw.n, err
 }
 
-func (p *PDF) LineBreak(style *FontStyle) {
-	if style == nil {
-		style = p.DefaultFontStyle
-	}
-	p.Engine.Ln(style.LineHeight)
+type countWriter struct {
+	w io.Writer
+	n int64
 }
 
-func (p *PDF) ToFile(filePath string) {
-	err := p.Engine.OutputFileAndClose(filePath)
-	if err != nil {
-		fmt.Println(err)
+func (c *countWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	return n, err
+}
+
+func (p *PDF) setError(err error) {
+	if p.err == nil && err != nil {
+		p.err = err
+		p.Engine.SetError(err)
 	}
 }
 
-func (p *PDF) ToBytes() []byte {
-	var b bytes.Buffer
-	writer := bufio.NewWriter(&b)
-	p.Engine.Output(writer)
-	err := writer.Flush()
-	if err != nil {
-		fmt.Println(err)
-		return nil
+func (p *PDF) outputError(err error) error {
+	if docErr := p.Err(); docErr != nil {
+		return docErr
 	}
-	return b.Bytes()
+	return err
 }
 
 func (p *PDF) initEngine(layout *PageLayout) {
-	p.Engine = gofpdf.New(layout.Orientation, "pt", layout.Paper, "")
+	orientation, size := layout.pageFormat(layout.unit())
+	p.Engine = gofpdf.NewCustom(&gofpdf.InitType{
+		OrientationStr: orientation,
+		UnitStr:        layout.unit(),
+		Size:           size,
+	})
 	if layout.PageMargin != nil {
 		p.Engine.SetMargins(layout.PageMargin.Left, layout.PageMargin.Top, layout.PageMargin.Right)
+		p.Engine.SetAutoPageBreak(true, layout.PageMargin.Bottom)
 	}
+	l, t, r, b := p.Engine.GetMargins()
+	p.margin = PageMargin{Top: t, Left: l, Right: r, Bottom: b}
+	p.marginBottom = b
+	p.Engine.SetHeaderFuncMode(p.beginPage, false)
+}
+
+/*
+layoutMargin returns the margins of pages of a layout, which are those of the
+document if the layout has none.
+*/
+func (p *PDF) layoutMargin(layout *PageLayout) *PageMargin {
+	if margin := layout.margin(p.PageLayout.unit()); margin != nil {
+		return margin
+	}
+	return &p.margin
+}
+
+/*
+setMargins sets the margins of the pages added next.
+*/
+func (p *PDF) setMargins(margin *PageMargin) {
+	p.Engine.SetMargins(margin.Left, margin.Top, margin.Right)
+	p.marginBottom = margin.Bottom
 }
 
 func (p *PDF) initDefaultFontStyle() {
@@ -218,15 +400,53 @@ func (p *PDF) initDefaultFontStyle() {
 
 func (p *PDF) initPageBodySize() {
 	pW, pH := p.Engine.GetPageSize()
-	l, t, r, b := p.Engine.GetMargins()
-	p.PageHeight = pH
-	p.PageWidth = pW
-	p.PageMarginTop = t
-	p.PageMarginBottom = b
-	p.PageMarginLeft = l
-	p.PageMarginRight = r
-	p.PageBodyHeight = pH - t - b
-	p.PageBodyWidth = pW - l - r
+	l, t, r, _ := p.Engine.GetMargins()
+	page := p.Engine.PageNo()
+	frame := pageFrame{
+		width:  pW,
+		height: pH,
+		top:    t,
+		bottom: p.marginBottom,
+		left:   l,
+		right:  r,
+		header: p.headers.forPage(page).height(),
+		footer: p.footers.forPage(page).height(),
+	}
+	if page > len(p.frames) {
+		p.frames = append(p.frames, frame)
+	} else {
+		p.frames[page-1] = frame
+	}
+	p.setFrame(frame)
+	// The engine breaks pages above the footer.
+	auto, _ := p.Engine.GetAutoPageBreak()
+	p.Engine.SetAutoPageBreak(auto, p.marginBottom+p.footerHeight)
+}
+
+/*
+pageFrame is the size and margins of a page, and the heights of its header
+and footer.
+*/
+type pageFrame struct {
+	width, height            float64
+	top, bottom, left, right float64
+	header, footer           float64
+}
+
+/*
+setFrame sets the page size fields of the document to those of a page.
+*/
+func (p *PDF) setFrame(frame pageFrame) {
+	p.headerHeight = frame.header
+	p.footerHeight = frame.footer
+	p.PageHeight = frame.height
+	p.PageWidth = frame.width
+	p.PageMarginTop = frame.top
+	p.PageMarginBottom = frame.bottom
+	p.PageMarginLeft = frame.left
+	p.PageMarginRight = frame.right
+	p.PageBodyHeight = frame.height - frame.top - frame.bottom - frame.header - frame.footer
+	p.PageBodyWidth = frame.width - frame.left - frame.right
 }
 
 func (p *PDF) processLayoutOpts(layout ...*PageLayout) *PageLayout {
@@ -244,14 +464,9 @@ func (p *PDF) processHAlign(align string) string {
 		return "C"
 	case AlignRight:
 		return "R"
+	case AlignJustify:
+		return "J"
 	default:
 		return "L"
 	}
 }
-
-func (p *PDF) initDefaultSupportingFonts() {
-	p.Engine.AddUTF8FontFromBytes(FontFamilyNotoSansTC, "", ttf_bytes.NotoSansTCRegular)
-	p.Engine.AddUTF8FontFromBytes(FontFamilyNotoSansTC, "B", ttf_bytes.NotoSansTCBold)
-	p.Engine.AddUTF8FontFromBytes(FontFamilyNotoSansSC, "", ttf_bytes.NotoSansSCRegular)
-	p.Engine.AddUTF8FontFromBytes(FontFamilyNotoSansSC, "B", ttf_bytes.NotoSansSCBold)
-}
@@ -1,15 +1,27 @@
 package gopdf
 
+import "github.com/jung-kurt/gofpdf"
+
 func NewPageLayout(orientation, paper string) *PageLayout {
 	layout := &PageLayout{}
 	layout.SetOrientation(orientation)
 	layout.SetPaper(paper)
+	layout.SetUnit(UnitPoint)
 	return layout
 }
 
+/*
+PageLayout is the paper, orientation and margins of the pages of a document,
+or of a single page added by AddPageWithLayout. PaperWidth and PaperHeight
+are the size of Custom paper in portrait orientation. They and the margins
+are in the Unit of the layout.
+*/
 type PageLayout struct {
 	Orientation      string      `json:"orientation"`
 	Paper            string      `json:"paper"`
+	Unit             string      `json:"unit"`
+	PaperWidth       float64     `json:"paper_width"`
+	PaperHeight      float64     `json:"paper_height"`
 	PageMargin       *PageMargin `json:"page_margin"`
 	DefaultFontStyle *FontStyle  `json:"default_font_style"`
 }
@@ -28,14 +40,126 @@ func (s *PageLayout) SetOrientation(orientation string) {
 }
 
 /*
-SetPaper sets the paper size for the PDF.
-By default, the paper size is A4.
+SetPaper sets the paper size for the PDF to one of the Paper sizes.
+By default, and for unknown sizes, the paper size is A4.
 */
 func (s *PageLayout) SetPaper(paper string) {
-	switch paper {
-	case PaperA3, PaperA4, PaperA5, PaperLetter, PaperLegal, PaperTabloid:
+	if _, ok := paperSizes[paper]; ok {
 		s.Paper = paper
-	default:
+	} else {
 		s.Paper = PaperA4
 	}
 }
+
+/*
+SetUnit sets the unit of the lengths of the layout. The unit of the layout
+of a document is the unit of every length of the document, such as margins,
+paddings and widths, except font sizes and line heights, which are in
+points. By default, the unit is the point.
+*/
+func (s *PageLayout) SetUnit(unit string) {
+	switch unit {
+	case UnitPoint, UnitMillimeter, UnitCentimeter, UnitInch:
+		s.Unit = unit
+	default:
+		s.Unit = UnitPoint
+	}
+}
+
+func (s *PageLayout) unit() string {
+	if s.Unit == "" {
+		return UnitPoint
+	}
+	return s.Unit
+}
+
+/*
+SetPaperSize sets a Custom paper size, in the unit of the layout, as the
+page is seen in portrait orientation.
+*/
+func (s *PageLayout) SetPaperSize(width, height float64) {
+	s.Paper = PaperCustom
+	s.PaperWidth = width
+	s.PaperHeight = height
+}
+
+/*
+pageSize returns the width and height of the pages of the layout.
+*/
+func (s *PageLayout) pageSize() (Length, Length) {
+	size := paperSizes[s.Paper]
+	if s.Paper == PaperCustom {
+		scale := unitPoints(s.Unit)
+		size = [2]Length{Length(s.PaperWidth * scale), Length(s.PaperHeight * scale)}
+	}
+	if size[0] <= 0 || size[1] <= 0 {
+		size = paperSizes[PaperA4]
+	}
+	if s.Orientation == OrientationLandscape {
+		return size[1], size[0]
+	}
+	return size[0], size[1]
+}
+
+/*
+pageFormat returns the orientation and size of the pages of the layout for
+the engine, in the given unit. The engine takes sizes in portrait
+orientation and turns them for landscape pages.
+*/
+func (s *PageLayout) pageFormat(unit string) (string, gofpdf.SizeType) {
+	w, h := s.pageSize()
+	return pageFormat(w.To(unit), h.To(unit))
+}
+
+/*
+margin returns the margins of the layout in the given unit, or nil if it
+has none.
+*/
+func (s *PageLayout) margin(unit string) *PageMargin {
+	m := s.PageMargin
+	if m == nil || s.unit() == unit {
+		return m
+	}
+	scale := unitPoints(s.Unit) / unitPoints(unit)
+	return NewPageMargin(m.Top*scale, m.Left*scale, m.Right*scale, m.Bottom*scale)
+}
+
+func pageFormat(w, h float64) (string, gofpdf.SizeType) {
+	if w > h {
+		return OrientationLandscape, gofpdf.SizeType{Wd: h, Ht: w}
+	}
+	return OrientationPortrait, gofpdf.SizeType{Wd: w, Ht: h}
+}
+
+/*
+paperSizes holds the size of each paper in portrait orientation.
+*/
+var paperSizes = map[string][2]Length{
+	PaperA0:         {Mm(841), Mm(1189)},
+	PaperA1:         {Mm(594), Mm(841)},
+	PaperA2:         {Mm(420), Mm(594)},
+	PaperA3:         {Mm(297), Mm(420)},
+	PaperA4:         {Mm(210), Mm(297)},
+	PaperA5:         {Mm(148), Mm(210)},
+	PaperA6:         {Mm(105), Mm(148)},
+	PaperA7:         {Mm(74), Mm(105)},
+	PaperB4:         {Mm(250), Mm(353)},
+	PaperB5:         {Mm(176), Mm(250)},
+	PaperB6:         {Mm(125), Mm(176)},
+	PaperC4:         {Mm(229), Mm(324)},
+	PaperC5:         {Mm(162), Mm(229)},
+	PaperC6:         {Mm(114), Mm(162)},
+	PaperDL:         {Mm(110), Mm(220)},
+	PaperJISB4:      {Mm(257), Mm(364)},
+	PaperJISB5:      {Mm(182), Mm(257)},
+	PaperJISB6:      {Mm(128), Mm(182)},
+	PaperLetter:     {In(8.5), In(11)},
+	PaperLegal:      {In(8.5), In(14)},
+	PaperTabloid:    {In(11), In(17)},
+	PaperHalfLetter: {In(5.5), In(8.5)},
+	PaperExecutive:  {In(7.25), In(10.5)},
+	PaperANSIC:      {In(17), In(22)},
+	PaperANSID:      {In(22), In(34)},
+	PaperANSIE:      {In(34), In(44)},
+	PaperCustom:     {},
+}
@@ -0,0 +1,75 @@
+package gopdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPaperSizes(t *testing.T) {
+	tests := []struct {
+		orientation, paper string
+		w, h               float64 // in points
+	}{
+		{"", "", 595.28, 841.89},
+		{OrientationPortrait, PaperA5, 419.53, 595.28},
+		{OrientationLandscape, PaperLetter, 792, 612},
+		{OrientationPortrait, PaperLegal, 612, 1008},
+		{OrientationPortrait, "Unknown", 595.28, 841.89},
+	}
+	for _, tt := range tests {
+		p := New(NewPageLayout(tt.orientation, tt.paper))
+		if !near(p.PageWidth, tt.w) || !near(p.PageHeight, tt.h) {
+			t.Errorf("%s %s: page is %.2f x %.2f, want %.2f x %.2f", tt.orientation, tt.paper, p.PageWidth, p.PageHeight, tt.w, tt.h)
+		}
+	}
+}
+
+func TestCustomPaperSize(t *testing.T) {
+	layout := NewPageLayout(OrientationLandscape, "")
+	layout.SetUnit(UnitMillimeter)
+	layout.SetPaperSize(100, 150)
+	p := New(layout)
+	if !near(p.PageWidth, 150) || !near(p.PageHeight, 100) {
+		t.Errorf("page is %.2f x %.2f mm, want 150 x 100", p.PageWidth, p.PageHeight)
+	}
+
+	layout.SetPaperSize(0, 150)
+	if w, h := layout.pageSize(); w != paperSizes[PaperA4][1] || h != paperSizes[PaperA4][0] {
+		t.Errorf("an empty custom size is %v x %v, want landscape A4", w, h)
+	}
+}
+
+func TestAddPageWithLayout(t *testing.T) {
+	p := newTestPDF(NewPageLayout(OrientationPortrait, PaperA5))
+	p.WriteText("portrait", nil)
+	p.AddPageWithLayout(NewPageLayout(OrientationLandscape, PaperLetter))
+	if !near(p.PageWidth, 792) || !near(p.PageHeight, 612) || !near(p.PageBodyWidth, 792-p.PageMarginLeft-p.PageMarginRight) {
+		t.Errorf("page is %.2f x %.2f, want landscape Letter", p.PageWidth, p.PageHeight)
+	}
+	// Text flowing on to the next page keeps the layout.
+	p.WriteText(strings.Repeat("flowing text ", 800), nil)
+	p.AddPage()
+	if !near(p.PageWidth, 419.53) {
+		t.Errorf("AddPage does not return to the document layout")
+	}
+	doc := output(t, p)
+	if n := bytes.Count(doc, []byte("/MediaBox [0 0 792.00 612.00]")); n < 2 {
+		t.Errorf("%d landscape Letter pages, want the page and its continuation", n)
+	}
+}
+
+func TestLayoutBottomMargin(t *testing.T) {
+	layout := NewPageLayout(OrientationPortrait, PaperA4)
+	layout.PageMargin = NewPageMargin(20, 20, 20, 100)
+	p := newTestPDF(layout)
+	for page := 1; page <= 2; page++ {
+		if _, margin := p.Engine.GetAutoPageBreak(); !near(margin, 100) {
+			t.Errorf("page %d breaks %.2f above the bottom, want 100", page, margin)
+		}
+		if !near(p.PageMarginBottom, 100) {
+			t.Errorf("page %d has a bottom margin of %.2f, want 100", page, p.PageMarginBottom)
+		}
+		p.AddPage()
+	}
+}
@@ -0,0 +1,320 @@
+package gopdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+func NewPageNumbering(style string, start int) *PageNumbering {
+	return &PageNumbering{
+		Style: style,
+		Start: start,
+	}
+}
+
+/*
+PageNumbering numbers the pages of a section of a document, as the labels
+shown by PDF viewers and the {section_page} placeholder. Style is one of the
+NumberStyle constants, Start is the number of the first page of the section
+and Prefix is put before each number, as in "A-1". Documents protected with
+the SetProtection of the engine cannot have prefixes.
+*/
+type PageNumbering struct {
+	Style  string `json:"style"`
+	Prefix string `json:"prefix"`
+	Start  int    `json:"start"`
+}
+
+func (n *PageNumbering) SetPrefix(prefix string) {
+	n.Prefix = prefix
+}
+
+/*
+label returns the label of the page at the given index in the section.
+*/
+func (n *PageNumbering) label(index int) string {
+	return n.Prefix + formatNumber(n.start()+index, n.Style)
+}
+
+func (n *PageNumbering) start() int {
+	if n.Start < 1 {
+		return 1
+	}
+	return n.Start
+}
+
+/*
+pdfStyle returns the PDF page label style of the numbering.
+*/
+func (n *PageNumbering) pdfStyle() string {
+	switch n.Style {
+	case NumberStyleRomanLower:
+		return "r"
+	case NumberStyleRomanUpper:
+		return "R"
+	case NumberStyleLettersLower:
+		return "a"
+	case NumberStyleLettersUpper:
+		return "A"
+	}
+	return "D"
+}
+
+/*
+pageSection is a numbering section starting at a page of the document.
+*/
+type pageSection struct {
+	page      int
+	numbering *PageNumbering
+}
+
+/*
+StartPageNumbering starts a numbering section at the current page, so that
+it and the following pages are numbered by the given numbering, until the
+next section. Pages before the first section are numbered from 1 in arabic
+numbers. Numbering sections also set the page labels of the document.
+*/
+func (p *PDF) StartPageNumbering(numbering *PageNumbering) {
+	page := p.Engine.PageNo()
+	if n := len(p.sections); n > 0 && p.sections[n-1].page == page {
+		p.sections[n-1].numbering = numbering
+		return
+	}
+	if len(p.sections) == 0 && page > 1 {
+		p.sections = append(p.sections, pageSection{page: 1, numbering: NewPageNumbering(NumberStyleArabic, 1)})
+	}
+	p.sections = append(p.sections, pageSection{page: page, numbering: numbering})
+}
+
+/*
+pageSection returns the index of the numbering section of a page, with its
+first page and its numbering.
+*/
+func (p *PDF) pageSection(page int) (int, int, *PageNumbering) {
+	for i := len(p.sections) - 1; i >= 0; i-- {
+		if p.sections[i].page <= page {
+			return i, p.sections[i].page, p.sections[i].numbering
+		}
+	}
+	return 0, 1, NewPageNumbering(NumberStyleArabic, 1)
+}
+
+/*
+sectionPageCount returns the number of pages of a numbering section in a
+document of the given number of pages.
+*/
+func (p *PDF) sectionPageCount(index, pages int) int {
+	if index+1 < len(p.sections) {
+		pages = p.sections[index+1].page - 1
+	}
+	if index < len(p.sections) {
+		return pages - p.sections[index].page + 1
+	}
+	return pages
+}
+
+/*
+Aliases of the page counts, replaced by the engine when the document is
+output. They hold every digit, so that the glyphs of the counts are kept in
+the font subsets, and are measured as the number of pages so far.
+*/
+const pagesAlias = "{0123456789}"
+
+var countAlias = regexp.MustCompile(`\{0123456789(?::\d+)?\}`)
+
+func sectionPagesAlias(index int) string {
+	return fmt.Sprintf("{0123456789:%d}", index)
+}
+
+/*
+resolvePlaceholders replaces the page placeholders of text written on the
+current page. Until the document is output, the page counts are replaced by
+aliases, so that the text around them is laid out before they are known.
+*/
+func (p *PDF) resolvePlaceholders(text string) string {
+	if !strings.Contains(text, "{") {
+		return text
+	}
+	page := p.Engine.PageNo()
+	index, first, numbering := p.pageSection(page)
+	pages, sectionPages := pagesAlias, sectionPagesAlias(index)
+	if p.pageCount > 0 {
+		pages = strconv.Itoa(p.pageCount)
+		sectionPages = strconv.Itoa(p.sectionPageCount(index, p.pageCount))
+	}
+	return strings.NewReplacer(
+		PlaceholderPage, strconv.Itoa(page),
+		PlaceholderPages, pages,
+		PlaceholderSectionPage, numbering.label(page-first),
+		PlaceholderSectionPages, sectionPages,
+	).Replace(text)
+}
+
+/*
+layoutText returns text as it is measured for layout, with its page
+placeholders resolved and the aliases of the page counts replaced by the
+number of pages so far, as the width of a realistic count.
+*/
+func (p *PDF) layoutText(text string) string {
+	text = p.resolvePlaceholders(text)
+	if p.pageCount > 0 || !strings.Contains(text, "{0123456789") {
+		return text
+	}
+	return countAlias.ReplaceAllLiteralString(text, strconv.Itoa(p.Engine.PageNo()))
+}
+
+/*
+registerPageAliases hands the page counts of a document of the given number
+of pages to the engine.
+*/
+func (p *PDF) registerPageAliases(pages int) {
+	p.Engine.RegisterAlias(pagesAlias, strconv.Itoa(pages))
+	for i := 0; i < max(len(p.sections), 1); i++ {
+		p.Engine.RegisterAlias(sectionPagesAlias(i), strconv.Itoa(p.sectionPageCount(i, pages)))
+	}
+}
+
+/*
+formatNumber formats a page number in a number style, like PDF viewers do.
+Numbers that cannot be written in the style are written in arabic numbers.
+*/
+func formatNumber(n int, style string) string {
+	if n < 1 {
+		return strconv.Itoa(n)
+	}
+	switch style {
+	case NumberStyleRomanLower:
+		return strings.ToLower(romanNumber(n))
+	case NumberStyleRomanUpper:
+		return romanNumber(n)
+	case NumberStyleLettersLower:
+		return strings.ToLower(letterNumber(n))
+	case NumberStyleLettersUpper:
+		return letterNumber(n)
+	}
+	return strconv.Itoa(n)
+}
+
+func romanNumber(n int) string {
+	if n >= 4000 {
+		return strconv.Itoa(n)
+	}
+	var b strings.Builder
+	for _, r := range []struct {
+		value  int
+		symbol string
+	}{
+		{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"},
+		{100, "C"}, {90, "XC"}, {50, "L"}, {40, "XL"},
+		{10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+	} {
+		for ; n >= r.value; n -= r.value {
+			b.WriteString(r.symbol)
+		}
+	}
+	return b.String()
+}
+
+/*
+letterNumber numbers pages A to Z, then AA to ZZ, AAA to ZZZ and so on.
+*/
+func letterNumber(n int) string {
+	letter := string(rune('A' + (n-1)%26))
+	return strings.Repeat(letter, (n-1)/26+1)
+}
+
+var (
+	trailerRoot  = regexp.MustCompile(`/Root (\d+) 0 R`)
+	trailerInfo  = regexp.MustCompile(`/Info (\d+) 0 R`)
+	trailerSize  = regexp.MustCompile(`/Size (\d+)`)
+	trailerCrypt = regexp.MustCompile(`/Encrypt \d+ 0 R`)
+	trailerID    = regexp.MustCompile(`/ID \[[^\n]*\]`)
+	startXref    = regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n?$`)
+)
+
+/*
+appendPageLabels adds the page labels of the numbering sections to a
+document output by the engine, as an incremental update replacing its
+catalog. A document not laid out as expected is reported with an
+EngineError, as is a protected document with label prefixes, since the key
+to encrypt their strings is private to the engine.
+*/
+func (p *PDF) appendPageLabels(doc []byte) ([]byte, error) {
+	i := bytes.LastIndex(doc, []byte("trailer"))
+	if i < 0 {
+		return nil, &EngineError{Err: errors.New("page labels: document trailer not found")}
+	}
+	trailer := doc[i:]
+	root, info, size, xref := trailerRoot.FindSubmatch(trailer), trailerInfo.FindSubmatch(trailer), trailerSize.FindSubmatch(trailer), startXref.FindSubmatch(trailer)
+	if root == nil || size == nil || xref == nil {
+		return nil, &EngineError{Err: errors.New("page labels: unexpected document trailer")}
+	}
+	encrypt := trailerCrypt.Find(trailer)
+	if encrypt != nil && slices.ContainsFunc(p.sections, func(s pageSection) bool { return s.numbering.Prefix != "" }) {
+		return nil, &EngineError{Err: errors.New("page labels: prefixes cannot be written to a protected document")}
+	}
+	start := bytes.Index(doc, []byte(fmt.Sprintf("\n%s 0 obj\n<<\n", root[1])))
+	if start < 0 {
+		return nil, &EngineError{Err: errors.New("page labels: document catalog not found")}
+	}
+	start += len(root[1]) + len(" 0 obj\n<<\n") + 1
+	end := bytes.Index(doc[start:], []byte("\n>>\nendobj"))
+	if end < 0 {
+		return nil, &EngineError{Err: errors.New("page labels: document catalog not found")}
+	}
+	catalog := doc[start : start+end]
+
+	var nums strings.Builder
+	for i, section := range p.sections {
+		n := section.numbering
+		if i > 0 {
+			nums.WriteString(" ")
+		}
+		fmt.Fprintf(&nums, "%d << /S /%s /St %d", section.page-1, n.pdfStyle(), n.start())
+		if n.Prefix != "" {
+			fmt.Fprintf(&nums, " /P %s", pdfTextString(n.Prefix))
+		}
+		nums.WriteString(" >>")
+	}
+
+	b := bytes.NewBuffer(doc)
+	if !bytes.HasSuffix(doc, []byte("\n")) {
+		b.WriteString("\n")
+	}
+	offset := b.Len()
+	fmt.Fprintf(b, "%s 0 obj\n<<\n%s\n/PageLabels << /Nums [%s] >>\n>>\nendobj\n", root[1], catalog, nums.String())
+	xrefOffset := b.Len()
+	fmt.Fprintf(b, "xref\n%s 1\n%010d 00000 n \n", root[1], offset)
+	fmt.Fprintf(b, "trailer\n<<\n/Size %s\n/Root %s 0 R\n", size[1], root[1])
+	if info != nil {
+		fmt.Fprintf(b, "/Info %s 0 R\n", info[1])
+	}
+	// The catalog keeps its object number, so its encrypted strings stay valid.
+	if encrypt != nil {
+		fmt.Fprintf(b, "%s\n", encrypt)
+		if id := trailerID.Find(trailer); id != nil {
+			fmt.Fprintf(b, "%s\n", id)
+		}
+	}
+	fmt.Fprintf(b, "/Prev %s\n>>\nstartxref\n%d\n%%%%EOF\n", xref[1], xrefOffset)
+	return b.Bytes(), nil
+}
+
+/*
+pdfTextString encodes text as a PDF text string in UTF-16.
+*/
+func pdfTextString(text string) string {
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, u := range utf16.Encode([]rune(text)) {
+		fmt.Fprintf(&b, "%04X", u)
+	}
+	b.WriteString(">")
+	return b.String()
+}
@@ -0,0 +1,156 @@
+package gopdf
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		n     int
+		style string
+		want  string
+	}{
+		{4, NumberStyleArabic, "4"},
+		{4, NumberStyleRomanLower, "iv"},
+		{1994, NumberStyleRomanUpper, "MCMXCIV"},
+		{4000, NumberStyleRomanUpper, "4000"},
+		{1, NumberStyleLettersUpper, "A"},
+		{28, NumberStyleLettersLower, "bb"},
+		{0, NumberStyleRomanUpper, "0"},
+		{7, "unknown", "7"},
+	}
+	for _, tt := range tests {
+		if got := formatNumber(tt.n, tt.style); got != tt.want {
+			t.Errorf("formatNumber(%d, %q) = %q, want %q", tt.n, tt.style, got, tt.want)
+		}
+	}
+}
+
+func TestPagePlaceholders(t *testing.T) {
+	p := newTestPDF()
+	p.SetFooter(NewPageRegion(10, func(pdf *PDF, page int) {
+		pdf.WriteText("{section_page} of {section_pages}, page {page} of {pages}", nil)
+	}))
+	p.StartPageNumbering(NewPageNumbering(NumberStyleRomanLower, 1))
+	p.WriteText("body", nil)
+	p.AddPage()
+	p.AddPage()
+	numbering := NewPageNumbering(NumberStyleArabic, 5)
+	numbering.SetPrefix("A-")
+	p.StartPageNumbering(numbering)
+	// Text in the body is laid out before the page count is known.
+	p.WriteText("{page}/{pages}", nil)
+
+	pages := pageOutput(t, p)
+	for i, want := range []string{
+		"i of 2, page 1 of 3",
+		"ii of 2, page 2 of 3",
+		"A-5 of 1, page 3 of 3",
+	} {
+		findText(t, pages[i], want)
+	}
+	findText(t, pages[2], "3/3")
+}
+
+func TestAlignedPageCount(t *testing.T) {
+	p := newTestPDF()
+	p.WriteTextBox("of {pages}", AlignRight, nil)
+	p.WriteTextBox("{pages} in all", AlignCenter, nil)
+	right := NewCellStyle(nil, nil, nil, AlignRight, AlignTop)
+	p.WriteTableRows(NewTable(nil).AddRow(NewCell("{pages}", right, 0, 0)))
+	p.WriteTextBox("of 1", AlignRight, nil)
+	p.WriteTextBox("1 in all", AlignCenter, nil)
+	p.WriteTableRows(NewTable(nil).AddRow(NewCell("1", right, 0, 0)))
+
+	drawn := map[string][]float64{}
+	for _, d := range texts(pageOutput(t, p)[0]) {
+		drawn[d.Text] = append(drawn[d.Text], d.X)
+	}
+	for _, text := range []string{"of 1", "1 in all", "1"} {
+		if x := drawn[text]; len(x) != 2 || math.Abs(x[0]-x[1]) > 0.05 {
+			t.Errorf("%q with the page count drawn at x %v, want as the number", text, x)
+		}
+	}
+}
+
+func TestWrappedPageCount(t *testing.T) {
+	lines := func(text string) []string {
+		p := newTestPDF()
+		p.WriteText(strings.Repeat(text, 60), nil)
+		var lines []string
+		for _, d := range texts(pageOutput(t, p)[0]) {
+			lines = append(lines, d.Text)
+		}
+		return lines
+	}
+	got, want := lines("page {pages} "), lines("page 1 ")
+	if !slices.Equal(got, want) {
+		t.Errorf("text with the page count wrapped as\n%q\nwant as the number\n%q", got, want)
+	}
+}
+
+func TestPageLabels(t *testing.T) {
+	p := newTestPDF()
+	p.WriteText("cover", nil)
+	p.AddPage()
+	numbering := NewPageNumbering(NumberStyleRomanUpper, 3)
+	numbering.SetPrefix("Ü")
+	p.StartPageNumbering(numbering)
+	doc := output(t, p)
+	want := "/PageLabels << /Nums [0 << /S /D /St 1 >> 1 << /S /R /St 3 /P <FEFF00DC> >>] >>"
+	if !bytes.Contains(doc, []byte(want)) {
+		t.Errorf("document has no page labels %s", want)
+	}
+	if !bytes.Contains(doc, []byte("/Prev ")) || !bytes.HasSuffix(doc, []byte("%%EOF\n")) {
+		t.Errorf("page labels are not appended as an incremental update")
+	}
+
+	// Documents without numbering sections have no page labels.
+	p = newTestPDF()
+	p.WriteText("plain", nil)
+	if bytes.Contains(output(t, p), []byte("/PageLabels")) {
+		t.Errorf("a document without sections has page labels")
+	}
+}
+
+func TestPageLabelsOfProtectedDocument(t *testing.T) {
+	p := newTestPDF()
+	p.Engine.SetProtection(0, "user", "owner")
+	p.StartPageNumbering(NewPageNumbering(NumberStyleRomanLower, 1))
+	doc := output(t, p)
+	trailer := doc[bytes.LastIndex(doc, []byte("trailer")):]
+	if !bytes.Contains(trailer, []byte("/Encrypt ")) || !bytes.Contains(trailer, []byte("/ID [")) {
+		t.Errorf("the page labels trailer drops the encryption:\n%s", trailer)
+	}
+
+	p = newTestPDF()
+	p.Engine.SetProtection(0, "user", "owner")
+	numbering := NewPageNumbering(NumberStyleArabic, 1)
+	numbering.SetPrefix("A-")
+	p.StartPageNumbering(numbering)
+	var engineErr *EngineError
+	if _, err := p.Bytes(); !errors.As(err, &engineErr) {
+		t.Errorf("Bytes of a protected document with label prefixes: error = %v, want *EngineError", err)
+	}
+}
+
+func TestAppendPageLabelsErrors(t *testing.T) {
+	p := newTestPDF()
+	p.StartPageNumbering(NewPageNumbering(NumberStyleArabic, 1))
+	for _, doc := range []string{
+		"%PDF-1.3\n%%EOF\n",
+		"%PDF-1.3\ntrailer\n<<\n/Size 3\n>>\nstartxref\n9\n%%EOF\n",
+		"%PDF-1.3\ntrailer\n<<\n/Size 3\n/Root 1 0 R\n>>\nstartxref\n9\n%%EOF\n",
+	} {
+		_, err := p.appendPageLabels([]byte(doc))
+		var engineErr *EngineError
+		if !errors.As(err, &engineErr) {
+			t.Errorf("appendPageLabels(%q) error = %v, want *EngineError", doc, err)
+		}
+	}
+}
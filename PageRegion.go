@@ -0,0 +1,183 @@
+package gopdf
+
+/*
+PageFunc draws the content of a page header or footer on a page, numbered
+from 1, with the usual Write methods of the document.
+*/
+type PageFunc func(pdf *PDF, page int)
+
+func NewPageRegion(height float64, draw PageFunc) *PageRegion {
+	return &PageRegion{
+		Height: height,
+		Draw:   draw,
+	}
+}
+
+/*
+PageRegion is a page header or footer. Headers take the given height below
+the top margin of a page and footers above its bottom margin, and the page
+body shrinks to leave room for them. Draw starts at the top left corner of
+the region. Regions are drawn when the document is output, once the number
+of pages is known, so that they never break the page and their text can
+hold any of the page placeholders.
+*/
+type PageRegion struct {
+	Height float64  `json:"height"`
+	Draw   PageFunc `json:"-"`
+}
+
+/*
+pageRegions holds the headers or footers of a document: first is used on
+the first page and even on even pages, if set, and all on the others.
+*/
+type pageRegions struct {
+	all   *PageRegion
+	first *PageRegion
+	even  *PageRegion
+}
+
+func (r *pageRegions) forPage(page int) *PageRegion {
+	switch {
+	case page == 1 && r.first != nil:
+		return r.first
+	case page%2 == 0 && r.even != nil:
+		return r.even
+	}
+	return r.all
+}
+
+func (r *PageRegion) height() float64 {
+	if r == nil {
+		return 0
+	}
+	return r.Height
+}
+
+/*
+SetHeader sets the header of every page, including the current one.
+Set it before writing to the current page, as the page body shrinks.
+*/
+func (p *PDF) SetHeader(region *PageRegion) {
+	p.headers.all = region
+	p.updatePage()
+}
+
+func (p *PDF) SetFooter(region *PageRegion) {
+	p.footers.all = region
+	p.updatePage()
+}
+
+/*
+SetFirstPageHeader sets the header of the first page instead of the one of
+SetHeader. An empty region leaves the first page without a header.
+*/
+func (p *PDF) SetFirstPageHeader(region *PageRegion) {
+	p.headers.first = region
+	p.updatePage()
+}
+
+func (p *PDF) SetFirstPageFooter(region *PageRegion) {
+	p.footers.first = region
+	p.updatePage()
+}
+
+/*
+SetEvenPageHeader sets the header of even pages instead of the one of
+SetHeader, which is then used on odd pages.
+*/
+func (p *PDF) SetEvenPageHeader(region *PageRegion) {
+	p.headers.even = region
+	p.updatePage()
+}
+
+func (p *PDF) SetEvenPageFooter(region *PageRegion) {
+	p.footers.even = region
+	p.updatePage()
+}
+
+/*
+beginPage sets up the body of a page the engine has started and draws the
+watermarks behind its content.
+*/
+func (p *PDF) beginPage() {
+	p.initPageBodySize()
+	p.Engine.SetXY(p.PageMarginLeft, p.pageTop())
+	p.drawWatermarks(p.pageWatermarks(p.Engine.PageNo(), true))
+}
+
+/*
+updatePage sets up the body of the current page again after its header or
+footer changed, moving down to the new top of the body if nothing was
+written yet.
+*/
+func (p *PDF) updatePage() {
+	top := p.pageTop()
+	p.initPageBodySize()
+	if p.Engine.GetY() <= top {
+		p.Engine.SetY(p.pageTop())
+	}
+}
+
+/*
+finish draws the headers, footers and watermarks over the content of every
+page and resolves the page counts, before the document is output. The
+document is finished once.
+*/
+func (p *PDF) finish() {
+	if p.pageCount > 0 {
+		return
+	}
+	p.pageCount = p.Engine.PageCount()
+	auto, margin := p.Engine.GetAutoPageBreak()
+	p.Engine.SetAutoPageBreak(false, margin)
+	l, t, r, _ := p.Engine.GetMargins()
+	for page := 1; page <= p.pageCount; page++ {
+		p.finishPage(page)
+	}
+	p.Engine.SetPage(p.pageCount)
+	p.Engine.SetMargins(l, t, r)
+	p.setFrame(p.frames[p.pageCount-1])
+	p.Engine.SetAutoPageBreak(auto, margin)
+	p.registerPageAliases(p.pageCount)
+}
+
+/*
+finishPage draws the header, footer and watermarks of a page at the end of
+its content. The drawing state at the end of the page is unknown, so the
+font and colours are selected again. The engine keeps the size of the last
+page, so pages of other sizes are drawn on shifted to their own.
+*/
+func (p *PDF) finishPage(page int) {
+	header, footer := p.headers.forPage(page), p.footers.forPage(page)
+	watermarks := p.pageWatermarks(page, false)
+	if (header == nil || header.Draw == nil) && (footer == nil || footer.Draw == nil) && len(watermarks) == 0 {
+		return
+	}
+	p.Engine.SetPage(page)
+	p.inRegion = true
+	defer func() { p.inRegion = false }()
+	frame := p.frames[page-1]
+	p.setFrame(frame)
+	width, height := p.Engine.GetPageSize()
+	p.Engine.SetMargins(frame.left, frame.top, frame.right)
+	// The right margin is negative on pages wider than the last one.
+	p.Engine.SetRightMargin(frame.right + width - frame.width)
+	if height != frame.height {
+		p.Engine.TransformBegin()
+		p.Engine.TransformTranslateY(height - frame.height)
+		defer p.Engine.TransformEnd()
+	}
+	p.fontKey = ""
+	p.Engine.SetDrawColor(p.Engine.GetDrawColor())
+	p.Engine.SetFillColor(p.Engine.GetFillColor())
+	p.Engine.SetLineWidth(p.Engine.GetLineWidth())
+	if header != nil && header.Draw != nil {
+		p.Engine.SetXY(p.PageMarginLeft, p.PageMarginTop)
+		header.Draw(p, page)
+	}
+	if footer != nil && footer.Draw != nil {
+		p.Engine.SetXY(p.PageMarginLeft, p.PageHeight-p.PageMarginBottom-footer.Height)
+		footer.Draw(p, page)
+	}
+	p.drawWatermarks(watermarks)
+}
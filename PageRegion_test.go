@@ -0,0 +1,92 @@
+package gopdf
+
+import (
+	"fmt"
+	"testing"
+)
+
+func regionText(text string) *PageRegion {
+	return NewPageRegion(15, func(pdf *PDF, page int) {
+		pdf.WriteText(fmt.Sprintf("%s %d", text, page), nil)
+	})
+}
+
+func TestHeadersAndFooters(t *testing.T) {
+	p := newTestPDF()
+	p.SetHeader(regionText("header"))
+	p.SetFirstPageHeader(NewPageRegion(0, nil))
+	p.SetEvenPageHeader(regionText("even"))
+	p.SetFooter(regionText("footer"))
+	for i := 0; i < 3; i++ {
+		if i > 0 {
+			p.AddPage()
+		}
+		p.WriteText(fmt.Sprintf("body %d", i+1), nil)
+	}
+	pages := pageOutput(t, p)
+	if len(pages) != 3 {
+		t.Fatalf("%d pages, want 3", len(pages))
+	}
+	k := p.Engine.GetConversionRatio()
+	for i, page := range pages {
+		n := i + 1
+		body := findText(t, page, fmt.Sprintf("body %d", n))
+		footer := findText(t, page, fmt.Sprintf("footer %d", n))
+		if footer.Y >= body.Y || footer.Y < p.PageMarginBottom*k {
+			t.Errorf("page %d: footer at %.2f, body at %.2f", n, footer.Y, body.Y)
+		}
+		var header string
+		switch n {
+		case 1:
+			for _, d := range texts(page) {
+				if d.Y > body.Y {
+					t.Errorf("page 1: %q drawn above the body", d.Text)
+				}
+			}
+			continue
+		case 2:
+			header = "even 2"
+		default:
+			header = "header 3"
+		}
+		// The body starts below the header region.
+		if got := findText(t, page, header).Y - body.Y; !near(got, 15*k) {
+			t.Errorf("page %d: body %.2f below the header, want %.2f", n, got, 15*k)
+		}
+	}
+}
+
+func TestHeaderShrinksPageBody(t *testing.T) {
+	p := newTestPDF()
+	height := p.PageBodyHeight
+	p.SetHeader(NewPageRegion(20, nil))
+	p.SetFooter(NewPageRegion(10, nil))
+	if got := p.PageBodyHeight; !near(got, height-30) {
+		t.Errorf("PageBodyHeight = %.2f, want %.2f", got, height-30)
+	}
+	if got := p.Engine.GetY(); !near(got, p.PageMarginTop+20) {
+		t.Errorf("Y = %.2f, want below the header", got)
+	}
+}
+
+func TestFooterTableAndParagraph(t *testing.T) {
+	p := newTestPDF()
+	p.SetFooter(NewPageRegion(40, func(pdf *PDF, page int) {
+		pdf.WriteTable([]*Cell{NewCell(fmt.Sprintf("cell %d", page), nil, 0, 0)}, nil)
+		pdf.LineBreak(nil)
+		pdf.WriteParagraph(NewParagraph().AddText(fmt.Sprintf("paragraph %d", page), nil), AlignLeft, nil)
+		pdf.WriteMarkdown(fmt.Sprintf("markdown %d", page), nil)
+	}))
+	p.WriteText("body 1", nil)
+	p.AddPage()
+	p.WriteText("body 2", nil)
+	pages := pageOutput(t, p)
+	if len(pages) != 2 || p.Engine.PageCount() != 2 {
+		t.Fatalf("%d pages output, %d in the engine, want 2", len(pages), p.Engine.PageCount())
+	}
+	for i, page := range pages {
+		for _, text := range []string{"cell", "paragraph", "markdown"} {
+			findText(t, page, fmt.Sprintf("%s %d", text, i+1))
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package gopdf
+
+import (
+	"slices"
+	"strings"
+)
+
+func NewSpan(text string, style *FontStyle) *Span {
+	return &Span{
+		Text:  text,
+		Style: style,
+	}
+}
+
+/*
+Span is a piece of text of a Paragraph in a single font style, which sets
+its font, size, color, bold, italic and underline.
+A span without a style uses the style of where the paragraph is written.
+*/
+type Span struct {
+	Text   string     `json:"text"`
+	Style  *FontStyle `json:"style"`
+	Link   string     `json:"link"`
+	Script string     `json:"script"`
+}
+
+/*
+SetLink makes the span a link to the given URL.
+*/
+func (s *Span) SetLink(link string) {
+	s.Link = link
+}
+
+/*
+SetScript draws the span as superscript or subscript, smaller and raised or
+lowered from the baseline of the line.
+*/
+func (s *Span) SetScript(script string) {
+	s.Script = script
+}
+
+// Superscript and subscript spans are drawn smaller, and raised or lowered
+// by a part of the font size of their style.
+const (
+	scriptScale = 0.6
+	scriptRise  = 0.35
+	scriptDrop  = 0.15
+)
+
+/*
+fontStyle returns the font style the span is drawn with, and how far its
+baseline is raised, in points.
+*/
+func (s *Span) fontStyle(base *FontStyle) (*FontStyle, float64) {
+	style := s.Style
+	if style == nil {
+		style = base
+	}
+	var rise float64
+	switch s.Script {
+	case ScriptSuperscript:
+		rise = scriptRise * style.FontSize
+	case ScriptSubscript:
+		rise = -scriptDrop * style.FontSize
+	default:
+		return style, 0
+	}
+	scaled := *style
+	scaled.FontSize *= scriptScale
+	scaled.LineHeight *= scriptScale
+	return &scaled, rise
+}
+
+func NewParagraph(spans ...*Span) *Paragraph {
+	return &Paragraph{Spans: spans}
+}
+
+/*
+Paragraph is text made of spans in different font styles, wrapped as a
+single flow. Each line is as high as the highest line height of its spans,
+and the spans of a line share its baseline.
+*/
+type Paragraph struct {
+	Spans []*Span `json:"spans"`
+}
+
+func (p *Paragraph) AddSpan(span *Span) *Paragraph {
+	p.Spans = append(p.Spans, span)
+	return p
+}
+
+func (p *Paragraph) AddText(text string, style *FontStyle) *Paragraph {
+	return p.AddSpan(NewSpan(text, style))
+}
+
+/*
+richRun is a piece of a line of a paragraph drawn with a single span.
+*/
+type richRun struct {
+	Span  *Span
+	Style *FontStyle
+	Text  string
+	Width float64
+	Rise  float64
+}
+
+/*
+richLine is a line of a paragraph. FontSize is the largest font size of its
+runs, which sets the baseline, and End marks the last line of a paragraph.
+*/
+type richLine struct {
+	Runs     []richRun
+	Width    float64
+	Height   float64
+	FontSize float64
+	End      bool
+}
+
+/*
+layoutParagraph wraps the spans of a paragraph into lines no wider than
+width, like wrapText. Spans without a style use base.
+*/
+func (p *PDF) layoutParagraph(par *Paragraph, base *FontStyle, width float64) []richLine {
+	var lines []richLine
+	var line richLine
+	var style *FontStyle
+	var rise float64
+	push := func(end bool) {
+		if len(line.Runs) == 0 && style != nil {
+			line.Height, line.FontSize = p.lineHeight(style), style.FontSize
+		}
+		line.End = end
+		lines = append(lines, line)
+		line = richLine{}
+	}
+	add := func(span *Span, text string, w float64) {
+		if n := len(line.Runs); n > 0 && line.Runs[n-1].Span == span {
+			line.Runs[n-1].Text += text
+			line.Runs[n-1].Width += w
+		} else {
+			line.Runs = append(line.Runs, richRun{Span: span, Style: style, Text: text, Width: w, Rise: rise})
+		}
+		line.Width += w
+		line.Height = max(line.Height, p.lineHeight(style))
+		line.FontSize = max(line.FontSize, style.FontSize)
+	}
+	for _, span := range par.Spans {
+		style, rise = span.fontStyle(base)
+		measure := p.textMeasure(style)
+		for i, para := range strings.Split(span.Text, "\n") {
+			if i > 0 {
+				push(true)
+			}
+			for _, token := range splitWords(para) {
+				tokenWidth := measure(token)
+				if line.Width+tokenWidth <= width {
+					add(span, token, tokenWidth)
+					continue
+				}
+				if len(line.Runs) > 0 {
+					push(false)
+					token = strings.TrimLeft(token, " ")
+					if tokenWidth = measure(token); tokenWidth <= width {
+						add(span, token, tokenWidth)
+						continue
+					}
+				}
+				// The word alone is wider than a line.
+				for {
+					head, rest := fitRunes(measure, token, width)
+					add(span, head, measure(head))
+					if rest == "" {
+						break
+					}
+					push(false)
+					token = rest
+				}
+			}
+		}
+	}
+	if len(line.Runs) > 0 || len(lines) == 0 {
+		if style == nil {
+			style = base
+		}
+		push(true)
+	}
+	return lines
+}
+
+func paragraphHeight(lines []richLine) float64 {
+	var h float64
+	for _, line := range lines {
+		h += line.Height
+	}
+	return h
+}
+
+/*
+paragraphWidths returns the width of the widest word of a paragraph and the
+width of its longest line.
+*/
+func (p *PDF) paragraphWidths(par *Paragraph, base *FontStyle) (minWidth, prefWidth float64) {
+	var lineWidth float64
+	for _, span := range par.Spans {
+		style, _ := span.fontStyle(base)
+		measure := p.textMeasure(style)
+		for i, para := range strings.Split(span.Text, "\n") {
+			if i > 0 {
+				prefWidth = max(prefWidth, lineWidth)
+				lineWidth = 0
+			}
+			for _, token := range splitWords(para) {
+				lineWidth += measure(token)
+				minWidth = max(minWidth, measure(strings.TrimLeft(token, " ")))
+			}
+		}
+	}
+	return minWidth, max(prefWidth, lineWidth)
+}
+
+/*
+WriteParagraph writes a paragraph across the page body from the left margin,
+aligning each line by align and breaking pages between lines. Spans without
+a style use the given style, or the default font style if it is nil.
+*/
+func (p *PDF) WriteParagraph(paragraph *Paragraph, align string, style *FontStyle) error {
+	if err := p.Err(); err != nil {
+		return err
+	}
+	if style == nil {
+		style = p.DefaultFontStyle
+	}
+	x := p.PageMarginLeft
+	for _, line := range p.layoutParagraph(paragraph, style, p.PageBodyWidth) {
+		if p.Engine.GetY()+line.Height > p.pageBreakTrigger() && p.Engine.GetY() > p.pageTop() && !p.inRegion {
+			p.continuePage()
+		}
+		y := p.Engine.GetY()
+		p.drawParagraphLine(line, x, y, p.PageBodyWidth, align)
+		p.Engine.SetXY(x, y+line.Height)
+	}
+	return p.Err()
+}
+
+/*
+drawParagraph draws the lines of a paragraph from x, y, each aligned within
+width, and moves below them.
+*/
+func (p *PDF) drawParagraph(lines []richLine, x, y, width float64, align string) {
+	for _, line := range lines {
+		if p.sliceShows(y) {
+			p.drawParagraphLine(line, x, y, width, align)
+		}
+		y += line.Height
+	}
+	p.Engine.SetXY(x, y)
+}
+
+/*
+drawParagraphLine draws a line of a paragraph from x, y, aligned within
+width, without breaking the page. The runs of the line share its baseline,
+from which superscript and subscript runs are raised or lowered. Justified
+lines are stretched at the spaces of their runs, but for the last line of a
+paragraph.
+*/
+func (p *PDF) drawParagraphLine(line richLine, x, y, width float64, align string) {
+	// The caller breaks the page before the line as a whole, so that runs
+	// lowered from its top never break the page in the middle of the line.
+	if auto, margin := p.Engine.GetAutoPageBreak(); auto {
+		p.Engine.SetAutoPageBreak(false, margin)
+		defer p.Engine.SetAutoPageBreak(true, margin)
+	}
+	cm := p.Engine.GetCellMargin()
+	p.Engine.SetCellMargin(0)
+	align = lineAlign(align, line.End)
+	runs := line.Runs
+	switch align {
+	case AlignCenter:
+		x += (width - line.Width) / 2
+	case AlignRight:
+		x += width - line.Width
+	case AlignJustify:
+		runs = p.justifyRuns(runs, width)
+	}
+	baseline := y + line.Height/2 + 0.3*p.Engine.PointToUnitConvert(line.FontSize)
+	for _, run := range runs {
+		// Move each run so that its baseline is that of the line.
+		runY := baseline - line.Height/2 - p.Engine.PointToUnitConvert(0.3*run.Style.FontSize+run.Rise)
+		p.Engine.SetXY(x, runY)
+		p.drawLine(run.Style, run.Width, line.Height, run.Text, align)
+		if run.Span.Link != "" {
+			p.Engine.LinkString(x, y, run.Width, line.Height, run.Span.Link)
+		}
+		x += run.Width
+	}
+	p.Engine.SetCellMargin(cm)
+}
+
+/*
+justifyRuns widens the runs of a line to fill width, sharing the extra width
+between the spaces of the line. Spaces ending the line are dropped.
+*/
+func (p *PDF) justifyRuns(runs []richRun, width float64) []richRun {
+	if len(runs) == 0 {
+		return runs
+	}
+	runs = slices.Clone(runs)
+	last := &runs[len(runs)-1]
+	if trimmed := strings.TrimRight(last.Text, " "); trimmed != last.Text {
+		last.Text, last.Width = trimmed, p.textMeasure(last.Style)(trimmed)
+	}
+	var spaces int
+	var lineWidth float64
+	for _, run := range runs {
+		spaces += strings.Count(run.Text, " ")
+		lineWidth += run.Width
+	}
+	if spaces == 0 || lineWidth >= width {
+		return runs
+	}
+	extra := (width - lineWidth) / float64(spaces)
+	for i := range runs {
+		runs[i].Width += extra * float64(strings.Count(runs[i].Text, " "))
+	}
+	return runs
+}
@@ -0,0 +1,89 @@
+package gopdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParagraphScripts(t *testing.T) {
+	p := newTestPDF()
+	sub := NewSpan("2", nil)
+	sub.SetScript(ScriptSubscript)
+	sup := NewSpan("3", nil)
+	sup.SetScript(ScriptSuperscript)
+	par := NewParagraph().AddText("H", nil).AddSpan(sub).AddText("O x", nil).AddSpan(sup)
+	if err := p.WriteParagraph(par, AlignLeft, nil); err != nil {
+		t.Fatalf("WriteParagraph: %v", err)
+	}
+	content := pageOutput(t, p)[0]
+	h, two, x, three := findText(t, content, "H"), findText(t, content, "2"), findText(t, content, "O x"), findText(t, content, "3")
+	if !near(h.Y, x.Y) {
+		t.Errorf("runs of the line at %.2f and %.2f, want one baseline", h.Y, x.Y)
+	}
+	if !near(h.Y-two.Y, scriptDrop*12) || !near(three.Y-h.Y, scriptRise*12) {
+		t.Errorf("subscript at %.2f and superscript at %.2f from the baseline %.2f", two.Y, three.Y, h.Y)
+	}
+	if !strings.Contains(before(t, content, "2"), " 7.20 Tf") {
+		t.Errorf("subscript is not drawn smaller")
+	}
+}
+
+func TestParagraphWraps(t *testing.T) {
+	p := newTestPDF()
+	bold := NewFontStyle("", 12, 0, nil, true, false, false)
+	par := NewParagraph().AddText(strings.Repeat("plain words ", 30), nil).AddText("bold\nnext", bold)
+	lines := p.layoutParagraph(par, p.DefaultFontStyle, 200)
+	if len(lines) < 3 {
+		t.Fatalf("%d lines, want the paragraph wrapped", len(lines))
+	}
+	for i, line := range lines {
+		if line.Width > 200 {
+			t.Errorf("line %d is %.2f wide", i, line.Width)
+		}
+	}
+	if last := lines[len(lines)-1]; !last.End || last.Runs[0].Text != "next" || !lines[len(lines)-2].End {
+		t.Errorf("the newline does not end a paragraph")
+	}
+	if got := p.ParagraphHeight(par, nil, 200); !near(got, float64(len(lines))*12) {
+		t.Errorf("ParagraphHeight = %.2f, want %d lines", got, len(lines))
+	}
+}
+
+func TestParagraphLineIsNotSplitAcrossPages(t *testing.T) {
+	p := newTestPDF()
+	sub := NewSpan("2", nil)
+	sub.SetScript(ScriptSubscript)
+	par := NewParagraph().AddText("H", nil).AddSpan(sub).AddText("O", nil)
+	// The line fits just above the page break, but its subscript run,
+	// drawn lower, would reach below it.
+	p.Engine.SetY(p.pageBreakTrigger() - 12 - 1)
+	if err := p.WriteParagraph(par, AlignLeft, nil); err != nil {
+		t.Fatalf("WriteParagraph: %v", err)
+	}
+	pages := pageOutput(t, p)
+	if len(pages) != 1 {
+		t.Fatalf("the line is drawn on %d pages", len(pages))
+	}
+	for _, text := range []string{"H", "2", "O"} {
+		findText(t, pages[0], text)
+	}
+
+	// A line below the page break moves to the next page as a whole.
+	p = newTestPDF()
+	p.Engine.SetY(p.pageBreakTrigger() - 6)
+	p.WriteParagraph(par, AlignLeft, nil)
+	pages = pageOutput(t, p)
+	if len(pages) != 2 || len(texts(pages[0])) != 0 || len(texts(pages[1])) != 3 {
+		t.Errorf("the line is not moved to the next page as a whole")
+	}
+}
+
+func TestParagraphLink(t *testing.T) {
+	p := newTestPDF()
+	link := NewSpan("site", nil)
+	link.SetLink("https://example.com")
+	p.WriteParagraph(NewParagraph().AddText("see ", nil).AddSpan(link), AlignLeft, nil)
+	if !strings.Contains(string(output(t, p)), "/URI (https://example.com)") {
+		t.Errorf("the span is not a link")
+	}
+}
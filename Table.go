@@ -0,0 +1,823 @@
+package gopdf
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+func NewTable(padding *Padding) *Table {
+	return &Table{Padding: padding}
+}
+
+/*
+Table is a table of rows of cells, written with PDF.WriteTableRows or
+row by row with a TableWriter.
+Header rows are repeated at the top of every page the table continues on,
+and footer rows end the table. The column widths come from Columns if set,
+or else from the first row without spanning cells.
+*/
+type Table struct {
+	Columns   []*Column    `json:"columns"`
+	Header    [][]*Cell    `json:"header"`
+	Rows      [][]*Cell    `json:"rows"`
+	Footer    [][]*Cell    `json:"footer"`
+	Totals    *TableTotals `json:"totals"`
+	Padding   *Padding     `json:"padding"`
+	AutoWidth bool         `json:"auto_width"`
+	Style     *TableStyle  `json:"style"`
+}
+
+func (t *Table) AddHeaderRow(cells ...*Cell) *Table {
+	t.Header = append(t.Header, cells)
+	return t
+}
+
+func (t *Table) AddRow(cells ...*Cell) *Table {
+	t.Rows = append(t.Rows, cells)
+	return t
+}
+
+func (t *Table) AddFooterRow(cells ...*Cell) *Table {
+	t.Footer = append(t.Footer, cells)
+	return t
+}
+
+/*
+SetTotals sets the rows of totals of the table, written before its footer
+rows and, if the table breaks across pages, at the ends and starts of pages.
+*/
+func (t *Table) SetTotals(totals *TableTotals) {
+	t.Totals = totals
+}
+
+/*
+SetStyle sets the table style layered on the styles of the cells,
+such as one of NewTableTheme.
+*/
+func (t *Table) SetStyle(style *TableStyle) {
+	t.Style = style
+}
+
+/*
+SetAutoWidth sets whether columns without a fixed width are sized to fit
+their content, instead of sharing the remaining width equally.
+A TableWriter measures the header rows and the first rows it is given.
+*/
+func (t *Table) SetAutoWidth(autoWidth bool) {
+	t.AutoWidth = autoWidth
+}
+
+/*
+WriteTableRows writes all rows of the table, breaking pages between rows.
+*/
+func (p *PDF) WriteTableRows(table *Table) error {
+	w := p.NewTableWriter(table)
+	if table.AutoWidth && len(table.Rows) > 0 {
+		w.start()
+		w.columns = w.resolveColumns(slices.Concat(table.Header, table.Rows, table.Footer))
+	}
+	for _, row := range table.Rows {
+		if err := w.WriteRow(row...); err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
+
+/*
+TableWriter writes the rows of a table one at a time, so that rows can be
+streamed without keeping the whole table in memory.
+A row that does not fit on the current page is moved to the next page,
+below the header rows of the table. Rows joined by cells spanning several
+rows are kept together.
+*/
+type TableWriter struct {
+	pdf        *PDF
+	table      *Table
+	x          float64
+	width      float64
+	columns    []float64
+	header     *tableBlock
+	rows       [][]*Cell
+	started    bool
+	rowsOnPage int
+	bodyRows   int
+	pages      int
+	styles     map[styleKey]*CellStyle
+
+	pageTotals    []aggregator
+	runningTotals []aggregator
+	totalStyles   []*CellStyle
+}
+
+/*
+NewTableWriter starts writing a table. The rows of the table are not used;
+they are given to WriteRow instead. Close must be called after the last row
+and writes the footer rows.
+*/
+func (p *PDF) NewTableWriter(table *Table) *TableWriter {
+	if table == nil {
+		table = NewTable(nil)
+	}
+	return &TableWriter{pdf: p, table: table, styles: map[styleKey]*CellStyle{}}
+}
+
+/*
+WriteRow writes a row of cells. Rows covered by a cell spanning several rows
+are held until the last of them is written.
+*/
+func (w *TableWriter) WriteRow(cells ...*Cell) error {
+	p := w.pdf
+	if err := p.Err(); err != nil {
+		return err
+	}
+	w.start()
+	w.rows = append(w.rows, cells)
+	if w.columns == nil {
+		// Hold the first rows to measure them for automatic column widths.
+		if w.table.AutoWidth && len(w.rows) < autoWidthSampleRows {
+			return nil
+		}
+		w.columns = w.resolveColumns(slices.Concat(w.table.Header, w.rows, w.table.Footer))
+	}
+	w.flush(false)
+	return p.Err()
+}
+
+/*
+Close finishes the table, writing held rows, and the header rows if no row
+was written.
+*/
+func (w *TableWriter) Close() error {
+	p := w.pdf
+	if err := p.Err(); err != nil {
+		return err
+	}
+	w.start()
+	if w.columns == nil {
+		w.columns = w.resolveColumns(slices.Concat(w.table.Header, w.rows, w.table.Footer))
+	}
+	w.flush(true)
+	if block := w.endBlock(); block != nil {
+		if w.rowsOnPage > 0 && p.Engine.GetY()+block.height() > p.pageBreakTrigger() && !p.inRegion {
+			w.breakPage()
+			block = w.endBlock()
+		}
+		w.writeBlock(block, false)
+	}
+	if w.rowsOnPage == 0 {
+		w.drawHeader()
+	}
+	if padding := w.table.Padding; padding != nil && padding.Bottom > 0 {
+		p.Engine.Ln(padding.Bottom)
+	}
+	p.Engine.SetX(p.PageMarginLeft)
+	return p.Err()
+}
+
+/*
+flush writes the held rows as blocks, keeping the rows of a pending row span
+unless final is set.
+*/
+func (w *TableWriter) flush(final bool) {
+	for len(w.rows) > 0 {
+		n := blockRows(w.rows, len(w.columns))
+		if n == 0 {
+			if !final {
+				return
+			}
+			n = len(w.rows)
+		}
+		block, _ := placeCells(w.rows[:n], len(w.columns))
+		w.rows = w.rows[n:]
+		w.table.Style.styleBlock(block, tableBody, w.bodyRows, w.styles)
+		w.bodyRows += n
+		w.writeBlock(block, true)
+		w.addTotals(block)
+	}
+	w.rows = nil
+}
+
+func (w *TableWriter) start() {
+	if w.started {
+		return
+	}
+	w.started = true
+	p := w.pdf
+	w.x = p.PageMarginLeft
+	w.width = p.PageBodyWidth
+	if padding := w.table.Padding; padding != nil {
+		if padding.Top > 0 {
+			p.Engine.Ln(padding.Top)
+		}
+		w.x += padding.Left
+		w.width -= padding.Left + padding.Right
+	}
+}
+
+func (w *TableWriter) resolveColumns(rows [][]*Cell) []float64 {
+	return w.pdf.tableWidths(w.table, rows, w.width)
+}
+
+/*
+tableWidths resolves the column widths of a table with the given rows.
+*/
+func (p *PDF) tableWidths(t *Table, rows [][]*Cell, width float64) []float64 {
+	if t.AutoWidth {
+		return p.autoColumns(rows, t.Columns, width)
+	}
+	if len(t.Columns) == 0 {
+		return tableColumns(rows, width)
+	}
+	widths := make([]*Cell, len(t.Columns))
+	for i, column := range t.Columns {
+		widths[i] = &Cell{Width: column.Width, WidthPercent: column.WidthPercent}
+	}
+	return cellWidths(widths, width)
+}
+
+/*
+writeBlock writes a block of rows, moving to the next page if it does not
+fit. Blocks of body rows leave room for the rows closing the page.
+*/
+func (w *TableWriter) writeBlock(block *tableBlock, body bool) {
+	p := w.pdf
+	p.measureBlock(block, w.columns)
+	h := block.height()
+	reserve := 0.0
+	if body {
+		reserve = w.closingHeight(block)
+	}
+	if w.rowsOnPage > 0 && p.Engine.GetY()+h+reserve > p.pageBreakTrigger() && !p.inRegion {
+		w.breakPage()
+	}
+	if w.rowsOnPage == 0 {
+		opening := w.openingBlock()
+		openingHeight := 0.0
+		if opening != nil {
+			openingHeight = opening.height()
+		}
+		// Keep the header rows together with the first rows of the page.
+		if p.Engine.GetY()+w.headerHeight()+openingHeight+h > p.pageBreakTrigger() && p.Engine.GetY() > p.pageTop() && !p.inRegion {
+			p.continuePage()
+		}
+		w.drawHeader()
+		if opening != nil {
+			p.drawBlock(opening, w.x, w.columns)
+		}
+	}
+	p.drawBlock(block, w.x, w.columns)
+	w.rowsOnPage += len(block.heights)
+}
+
+func (w *TableWriter) headerBlock() *tableBlock {
+	if w.header == nil {
+		w.header, _ = placeCells(w.table.Header, len(w.columns))
+		w.table.Style.styleBlock(w.header, tableHeader, 0, w.styles)
+		w.pdf.measureBlock(w.header, w.columns)
+	}
+	return w.header
+}
+
+func (w *TableWriter) drawHeader() {
+	if len(w.table.Header) > 0 {
+		w.pdf.drawBlock(w.headerBlock(), w.x, w.columns)
+	}
+}
+
+func (w *TableWriter) headerHeight() float64 {
+	if len(w.table.Header) == 0 {
+		return 0
+	}
+	return w.headerBlock().height()
+}
+
+/*
+tableBlock is a group of rows laid out on the column grid of a table.
+No cell of a block spans rows outside of it.
+*/
+type tableBlock struct {
+	cells     []blockCell
+	heights   []float64
+	baselines []float64 // offsets of the first baseline of each row
+}
+
+type blockCell struct {
+	Cell    *Cell
+	Row     int
+	Col     int
+	ColSpan int
+	RowSpan int
+}
+
+func (b *tableBlock) height() float64 {
+	var h float64
+	for _, rowHeight := range b.heights {
+		h += rowHeight
+	}
+	return h
+}
+
+/*
+placeCells places rows of cells on a grid of n columns, left to right,
+skipping the columns covered by cells spanning from the rows above.
+It reports whether a cell spans below the last row; such spans are cut at
+the last row of the block.
+*/
+func placeCells(rows [][]*Cell, n int) (*tableBlock, bool) {
+	block := &tableBlock{heights: make([]float64, len(rows)), baselines: make([]float64, len(rows))}
+	covered := make([]int, n)
+	for r, row := range rows {
+		col := 0
+		for _, cell := range row {
+			for col < n && covered[col] > 0 {
+				col++
+			}
+			if col >= n {
+				break
+			}
+			colSpan := min(cell.colSpan(), n-col)
+			block.cells = append(block.cells, blockCell{
+				Cell:    cell,
+				Row:     r,
+				Col:     col,
+				ColSpan: colSpan,
+				RowSpan: min(cell.rowSpan(), len(rows)-r),
+			})
+			for c := col; c < col+colSpan; c++ {
+				covered[c] = cell.rowSpan()
+			}
+			col += colSpan
+		}
+		for c := range covered {
+			if covered[c] > 0 {
+				covered[c]--
+			}
+		}
+	}
+	for _, rows := range covered {
+		if rows > 0 {
+			return block, true
+		}
+	}
+	return block, false
+}
+
+/*
+blockRows returns the number of rows that form the first block of rows,
+or 0 if a cell spans below the last row.
+*/
+func blockRows(rows [][]*Cell, n int) int {
+	for i := 1; i <= len(rows); i++ {
+		if _, pending := placeCells(rows[:i], n); !pending {
+			return i
+		}
+	}
+	return 0
+}
+
+/*
+tableColumns resolves the column widths of a table from the first row
+without spanning cells. If every row has one, the columns share the width
+equally.
+*/
+func tableColumns(rows [][]*Cell, tableWidth float64) []float64 {
+	n, row := widthRow(rows)
+	if row != nil {
+		return cellWidths(row, tableWidth)
+	}
+	columns := make([]float64, n)
+	for i := range columns {
+		columns[i] = tableWidth / float64(n)
+	}
+	return columns
+}
+
+/*
+widthRow returns the number of columns of a table and its first row without
+spanning cells, which sets the column widths, or nil if there is none.
+*/
+func widthRow(rows [][]*Cell) (int, []*Cell) {
+	var n int
+	for _, row := range rows {
+		var count int
+		for _, cell := range row {
+			count += cell.colSpan()
+		}
+		n = max(n, count)
+	}
+	for _, row := range rows {
+		if len(row) == n && !hasSpans(row) {
+			return n, row
+		}
+	}
+	return n, nil
+}
+
+func hasSpans(row []*Cell) bool {
+	for _, cell := range row {
+		if cell.colSpan() > 1 || cell.rowSpan() > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+measureBlock sets the row heights of a block to fit the wrapped text of its
+cells. Cells spanning several rows grow the last of them when needed.
+*/
+func (p *PDF) measureBlock(block *tableBlock, columns []float64) {
+	for _, c := range block.cells {
+		if c.Cell.Style.VAlign == AlignBaseline {
+			block.baselines[c.Row] = max(block.baselines[c.Row], p.cellBaseline(c.Cell))
+		}
+	}
+	for _, c := range block.cells {
+		if c.RowSpan == 1 {
+			block.heights[c.Row] = max(block.heights[c.Row], p.blockCellHeight(block, c, columns))
+		}
+	}
+	var spanning []blockCell
+	for _, c := range block.cells {
+		if c.RowSpan > 1 {
+			spanning = append(spanning, c)
+		}
+	}
+	sort.SliceStable(spanning, func(i, j int) bool { return spanning[i].RowSpan < spanning[j].RowSpan })
+	for _, c := range spanning {
+		var h float64
+		for _, rowHeight := range block.heights[c.Row : c.Row+c.RowSpan] {
+			h += rowHeight
+		}
+		if need := p.blockCellHeight(block, c, columns) - h; need > 0 {
+			block.heights[c.Row+c.RowSpan-1] += need
+		}
+	}
+}
+
+/*
+blockCellHeight returns the height a cell needs in a block, including the
+offset that aligns it to the baseline of its row.
+*/
+func (p *PDF) blockCellHeight(block *tableBlock, c blockCell, columns []float64) float64 {
+	h := p.cellHeight(c.Cell, spanWidth(columns, c.Col, c.ColSpan))
+	if c.Cell.Style.VAlign == AlignBaseline {
+		h += block.baselines[c.Row] - p.cellBaseline(c.Cell)
+	}
+	return h
+}
+
+/*
+cellBaseline returns the offset of the first baseline of a cell from its top.
+*/
+func (p *PDF) cellBaseline(cell *Cell) float64 {
+	top, _, _, _ := p.cellInsets(cell)
+	return top + p.firstBaseline(cell.Style.FontStyle)
+}
+
+/*
+firstBaseline returns the offset of the first baseline of text in the style
+from the top of its line, as placed by the engine.
+*/
+func (p *PDF) firstBaseline(style *FontStyle) float64 {
+	return p.lineHeight(style)/2 + 0.3*p.Engine.PointToUnitConvert(style.FontSize)
+}
+
+/*
+drawBlock draws a block at the current Y and moves below it.
+Blocks are drawn without engine page breaks, so that they are never split;
+the caller moves them to a new page beforehand. A block that still does not
+fit, as it is taller than the page, is split across pages instead, but for
+headers and footers, which are drawn where they are.
+*/
+func (p *PDF) drawBlock(block *tableBlock, x float64, columns []float64) {
+	auto, margin := p.Engine.GetAutoPageBreak()
+	p.Engine.SetAutoPageBreak(false, margin)
+	defer p.Engine.SetAutoPageBreak(auto, margin)
+	y := p.Engine.GetY()
+	if y+block.height() > p.pageBreakTrigger() && !p.inRegion {
+		p.drawSplitBlock(block, x, columns)
+		return
+	}
+	p.drawBlockCells(block, x, y, columns)
+	p.Engine.SetXY(p.PageMarginLeft, y+block.height())
+}
+
+/*
+drawBlockCells draws the cells of a block with its top at y.
+*/
+func (p *PDF) drawBlockCells(block *tableBlock, x, y float64, columns []float64) {
+	// Fill every cell before drawing borders, so that fills do not cover the
+	// borders of neighbouring cells.
+	for _, c := range block.cells {
+		if style := c.Cell.Style; style.FillColor != nil {
+			cx, cy, w, h := block.cellRect(c, x, y, columns)
+			style.SetupFillColor(p)
+			p.Engine.Rect(cx, cy, w, h, "F")
+		}
+	}
+	for _, c := range block.cells {
+		cx, cy, w, h := block.cellRect(c, x, y, columns)
+		c.Cell.Style.BorderStyle.Draw(p, cx, cy, w, h)
+	}
+	for _, c := range block.cells {
+		cx, cy, w, h := block.cellRect(c, x, y, columns)
+		p.drawCellText(c.Cell, cx, cy, w, h, block.baselines[c.Row])
+	}
+}
+
+/*
+blockSlice is the part of the page showing a slice of a block split across
+pages. Lines of text are drawn on the slice their top falls in.
+*/
+type blockSlice struct {
+	top    float64
+	bottom float64
+}
+
+/*
+drawSplitBlock draws a block from the current Y across as many pages as it
+needs. Each page shows the next slice of the block, clipped to the body of
+the page and cut between lines of text, so that no line is split.
+*/
+func (p *PDF) drawSplitBlock(block *tableBlock, x float64, columns []float64) {
+	defer func() { p.slice = nil }()
+	var offset float64
+	for {
+		y := p.Engine.GetY()
+		room := p.pageBreakTrigger() - y
+		if room <= 0 && y > p.pageTop() {
+			p.continuePage()
+			continue
+		}
+		// A page without room for the block, as its header and footer fill
+		// it, takes the rest of the block.
+		last := block.height()-offset <= room || room <= 0
+		cut := block.height()
+		if !last {
+			cut = p.blockCut(block, x, columns, offset, offset+room)
+		}
+		p.slice = &blockSlice{top: y, bottom: y + cut - offset}
+		clipTop, clipBottom := y, y+cut-offset
+		if offset == 0 {
+			clipTop = 0
+		}
+		if last {
+			clipBottom = p.PageHeight
+		}
+		p.Engine.ClipRect(0, clipTop, p.PageWidth, clipBottom-clipTop, false)
+		p.drawBlockCells(block, x, y-offset, columns)
+		p.Engine.ClipEnd()
+		if last {
+			p.Engine.SetXY(p.PageMarginLeft, y+cut-offset)
+			return
+		}
+		offset = cut
+		p.continuePage()
+	}
+}
+
+/*
+sliceShows reports whether a line with its top at y is drawn on the slice of
+a split block being drawn, if any.
+*/
+func (p *PDF) sliceShows(y float64) bool {
+	const tolerance = 1e-6
+	return p.slice == nil || y >= p.slice.top-tolerance && y < p.slice.bottom-tolerance
+}
+
+/*
+blockCut returns where to cut a block, measured from its top, for a slice
+running from the offset from to at most to. The cut is moved up to the top
+of any line of text it would split, unless the line starts the slice.
+*/
+func (p *PDF) blockCut(block *tableBlock, x float64, columns []float64, from, to float64) float64 {
+	const tolerance = 1e-6
+	var bands []lineBand
+	for _, c := range block.cells {
+		cx, cy, w, h := block.cellRect(c, x, 0, columns)
+		bands = append(bands, p.cellLineBands(c.Cell, cx, cy, w, h, block.baselines[c.Row])...)
+	}
+	cut := to
+	for moved := true; moved; {
+		moved = false
+		for _, band := range bands {
+			if band.top > from+tolerance && band.top < cut && band.top+band.height > cut+tolerance {
+				cut, moved = band.top, true
+			}
+		}
+	}
+	if cut <= from+tolerance {
+		return to
+	}
+	return cut
+}
+
+/*
+cellRect returns the area of a cell of a block drawn at x, y.
+*/
+func (b *tableBlock) cellRect(c blockCell, x, y float64, columns []float64) (float64, float64, float64, float64) {
+	for _, rowHeight := range b.heights[:c.Row] {
+		y += rowHeight
+	}
+	var h float64
+	for _, rowHeight := range b.heights[c.Row : c.Row+c.RowSpan] {
+		h += rowHeight
+	}
+	return x + spanWidth(columns, 0, c.Col), y, spanWidth(columns, c.Col, c.ColSpan), h
+}
+
+/*
+drawCellText draws the wrapped text or the content of a cell within its area
+less its padding, aligned by its style. Baseline aligned text is moved down
+to the given row baseline; other content is aligned to the top instead.
+*/
+func (p *PDF) drawCellText(cell *Cell, x, y, w, h, baseline float64) {
+	style := cell.Style
+	_, left, right, _ := p.cellInsets(cell)
+	if content := cell.content(); content != nil {
+		contentX, contentY, contentWidth, contentHeight := p.contentRect(cell, content, x, y, w, h)
+		content.draw(p, style, contentX, contentY, contentWidth, contentHeight)
+		return
+	}
+	lineHeight := p.lineHeight(style.FontStyle)
+	lines, ends := p.cellLines(cell, w)
+	y = p.cellTextTop(cell, y, h, float64(len(lines))*lineHeight, baseline)
+	// The insets already hold the cell margin.
+	cm := p.Engine.GetCellMargin()
+	p.Engine.SetCellMargin(0)
+	for i, line := range lines {
+		lineY := y + float64(i)*lineHeight
+		if !p.sliceShows(lineY) {
+			continue
+		}
+		p.Engine.SetXY(x+left, lineY)
+		p.drawLine(style.FontStyle, w-left-right, lineHeight, line, lineAlign(style.HAlign, ends[i]))
+	}
+	p.Engine.SetCellMargin(cm)
+}
+
+/*
+contentRect returns the area of the content of a cell drawn in the area at
+x, y, aligned within it less its padding.
+*/
+func (p *PDF) contentRect(cell *Cell, content cellContent, x, y, w, h float64) (float64, float64, float64, float64) {
+	style := cell.Style
+	top, left, right, bottom := p.cellInsets(cell)
+	contentWidth, contentHeight := content.size(p, style, w-left-right)
+	contentX := x + left
+	switch style.HAlign {
+	case AlignCenter:
+		contentX += (w - left - right - contentWidth) / 2
+	case AlignRight:
+		contentX = x + w - right - contentWidth
+	}
+	contentY := y + top
+	switch style.VAlign {
+	case AlignMiddle:
+		contentY += (h - top - bottom - contentHeight) / 2
+	case AlignBottom:
+		contentY = y + h - bottom - contentHeight
+	}
+	return contentX, contentY, contentWidth, contentHeight
+}
+
+/*
+cellTextTop returns the Y of the first line of the text of a cell drawn in
+the area at y of height h.
+*/
+func (p *PDF) cellTextTop(cell *Cell, y, h, textHeight, baseline float64) float64 {
+	top, _, _, bottom := p.cellInsets(cell)
+	switch cell.Style.VAlign {
+	case AlignMiddle:
+		return y + top + (h-top-bottom-textHeight)/2
+	case AlignBottom:
+		return y + h - bottom - textHeight
+	case AlignBaseline:
+		return y + baseline - p.firstBaseline(cell.Style.FontStyle)
+	}
+	return y + top
+}
+
+/*
+lineBand is the vertical extent of a line of text.
+*/
+type lineBand struct {
+	top    float64
+	height float64
+}
+
+/*
+cellLineBands returns the lines of the text or the paragraph of a cell drawn
+in the area at x, y. Other content has no lines.
+*/
+func (p *PDF) cellLineBands(cell *Cell, x, y, w, h, baseline float64) []lineBand {
+	var bands []lineBand
+	if content := cell.content(); content != nil {
+		paragraph, ok := content.(paragraphContent)
+		if !ok {
+			return nil
+		}
+		_, top, width, _ := p.contentRect(cell, content, x, y, w, h)
+		for _, line := range p.layoutParagraph(paragraph.paragraph, cell.Style.FontStyle, width) {
+			bands = append(bands, lineBand{top: top, height: line.Height})
+			top += line.Height
+		}
+		return bands
+	}
+	lineHeight := p.lineHeight(cell.Style.FontStyle)
+	lines, _ := p.cellLines(cell, w)
+	top := p.cellTextTop(cell, y, h, float64(len(lines))*lineHeight, baseline)
+	for i := range lines {
+		bands = append(bands, lineBand{top: top + float64(i)*lineHeight, height: lineHeight})
+	}
+	return bands
+}
+
+func spanWidth(columns []float64, col, span int) float64 {
+	var w float64
+	for _, width := range columns[col : col+span] {
+		w += width
+	}
+	return w
+}
+
+/*
+cellWidths resolves the widths of a row of cells within the table width.
+Cells without Width or WidthPercent share the remaining width equally.
+*/
+func cellWidths(cells []*Cell, tableWidth float64) []float64 {
+	widths := make([]float64, len(cells))
+	var emptyWidth int
+	var usedWidth float64
+	for i, cell := range cells {
+		switch {
+		case cell.Width > 0:
+			widths[i] = cell.Width
+		case cell.WidthPercent > 0:
+			widths[i] = tableWidth * cell.WidthPercent
+		default:
+			emptyWidth++
+			continue
+		}
+		usedWidth += widths[i]
+	}
+	for i := range widths {
+		if widths[i] == 0 {
+			widths[i] = (tableWidth - usedWidth) / float64(emptyWidth)
+		}
+	}
+	return widths
+}
+
+/*
+cellLines returns the lines of a cell as wrapped within its width, and which
+of them end a paragraph.
+*/
+func (p *PDF) cellLines(cell *Cell, width float64) ([]string, []bool) {
+	text := strings.TrimRight(strings.TrimSpace(cell.Text), "\n")
+	_, left, right, _ := p.cellInsets(cell)
+	measure := p.textMeasure(cell.Style.FontStyle)
+	return wrapParagraphs(measure, text, width-left-right)
+}
+
+/*
+cellInsets returns the space between the borders of a cell and its text,
+which is the padding of the cell or its style, or else the cell margin of
+the engine on the left and right.
+*/
+func (p *PDF) cellInsets(cell *Cell) (top, left, right, bottom float64) {
+	if padding := cell.padding(); padding != nil {
+		return padding.Top, padding.Left, padding.Right, padding.Bottom
+	}
+	cm := p.Engine.GetCellMargin()
+	return 0, cm, cm, 0
+}
+
+/*
+cellHeight returns the height of the wrapped text or the content of a cell
+and its padding.
+*/
+func (p *PDF) cellHeight(cell *Cell, width float64) float64 {
+	top, left, right, bottom := p.cellInsets(cell)
+	if content := cell.content(); content != nil {
+		_, h := content.size(p, cell.Style, width-left-right)
+		return top + h + bottom
+	}
+	lines, _ := p.cellLines(cell, width)
+	return top + float64(len(lines))*p.lineHeight(cell.Style.FontStyle) + bottom
+}
+
+func (p *PDF) pageBreakTrigger() float64 {
+	return p.PageHeight - p.PageMarginBottom - p.footerHeight
+}
+
+/*
+pageTop returns the Y at which content starts on a new page, below the page
+header.
+*/
+func (p *PDF) pageTop() float64 {
+	return p.PageMarginTop + p.headerHeight
+}
@@ -0,0 +1,248 @@
+package gopdf
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+/*
+autoWidthSampleRows is the number of rows a TableWriter holds and measures
+for automatic column widths before writing any of them.
+*/
+const autoWidthSampleRows = 100
+
+/*
+autoWidthSlack is added to measured widths, so that rounding in the cell
+insets never wraps text that was measured to fit.
+*/
+const autoWidthSlack = 0.001
+
+/*
+autoColumns sizes the columns of a table to their content, like the automatic
+table layout of HTML. Each column has a minimum width, that of its widest
+word, and a preferred width, that of its longest line. If the preferred
+widths fit, the columns get them and share the rest of the width in their
+proportion; otherwise the columns get their minimum widths and share the
+rest in proportion to how much more they prefer. Columns with a fixed width
+keep it, and MinWidth and MaxWidth bound the others.
+*/
+func (p *PDF) autoColumns(rows [][]*Cell, columns []*Column, tableWidth float64) []float64 {
+	if len(columns) == 0 {
+		columns = rowColumns(rows)
+	}
+	n := len(columns)
+	widths := make([]float64, n)
+	fixed := make([]bool, n)
+	available := tableWidth
+	for i, column := range columns {
+		switch {
+		case column.Width > 0:
+			widths[i] = column.Width
+		case column.WidthPercent > 0:
+			widths[i] = tableWidth * column.WidthPercent
+		default:
+			continue
+		}
+		fixed[i] = true
+		available -= widths[i]
+	}
+
+	minWidths, prefWidths := p.columnContentWidths(rows, widths, fixed)
+
+	var auto []int
+	var sumMin, sumPref float64
+	for i, column := range columns {
+		if fixed[i] {
+			continue
+		}
+		minWidths[i] = max(minWidths[i], column.MinWidth)
+		if column.MaxWidth > 0 {
+			minWidths[i] = min(minWidths[i], column.MaxWidth)
+			prefWidths[i] = min(prefWidths[i], column.MaxWidth)
+		}
+		prefWidths[i] = max(prefWidths[i], minWidths[i])
+		auto = append(auto, i)
+		sumMin += minWidths[i]
+		sumPref += prefWidths[i]
+	}
+	available = max(available, 0)
+	switch {
+	case len(auto) == 0:
+	case sumPref <= available:
+		for _, i := range auto {
+			widths[i] = prefWidths[i]
+		}
+		growColumns(widths, columns, auto, available-sumPref)
+	case sumMin <= available:
+		ratio := (available - sumMin) / (sumPref - sumMin)
+		for _, i := range auto {
+			widths[i] = minWidths[i] + (prefWidths[i]-minWidths[i])*ratio
+		}
+	default:
+		// Not even the words fit, so they are broken between characters.
+		for _, i := range auto {
+			widths[i] = minWidths[i] * available / sumMin
+		}
+	}
+	return widths
+}
+
+/*
+columnContentWidths returns the minimum and preferred widths of the columns
+of a table. Cells spanning several columns share what they need between
+the spanned columns without a fixed width.
+*/
+func (p *PDF) columnContentWidths(rows [][]*Cell, widths []float64, fixed []bool) ([]float64, []float64) {
+	n := len(widths)
+	minWidths := make([]float64, n)
+	prefWidths := make([]float64, n)
+	block, _ := placeCells(rows, n)
+	cells := block.cells
+	sort.SliceStable(cells, func(i, j int) bool { return cells[i].ColSpan < cells[j].ColSpan })
+	for _, c := range cells {
+		minWidth, prefWidth := p.cellContentWidths(c.Cell)
+		if c.ColSpan == 1 {
+			minWidths[c.Col] = max(minWidths[c.Col], minWidth)
+			prefWidths[c.Col] = max(prefWidths[c.Col], prefWidth)
+			continue
+		}
+		spreadWidth(minWidths, widths, fixed, c.Col, c.ColSpan, minWidth)
+		spreadWidth(prefWidths, widths, fixed, c.Col, c.ColSpan, prefWidth)
+	}
+	return minWidths, prefWidths
+}
+
+/*
+rowColumns returns columns with the widths of the cells of the row setting
+the column widths, or columns without widths if there is no such row.
+*/
+func rowColumns(rows [][]*Cell) []*Column {
+	n, row := widthRow(rows)
+	columns := make([]*Column, n)
+	for i := range columns {
+		columns[i] = NewColumn("", 0, 0)
+		if row != nil {
+			columns[i].Width, columns[i].WidthPercent = row[i].Width, row[i].WidthPercent
+		}
+	}
+	return columns
+}
+
+/*
+spreadWidth grows the columns spanned by a cell that needs width, sharing the
+missing width equally between the columns without a fixed width.
+*/
+func spreadWidth(needs, widths []float64, fixed []bool, col, span int, width float64) {
+	var have float64
+	var free int
+	for i := col; i < col+span; i++ {
+		if fixed[i] {
+			have += widths[i]
+		} else {
+			have += needs[i]
+			free++
+		}
+	}
+	if width <= have || free == 0 {
+		return
+	}
+	for i := col; i < col+span; i++ {
+		if !fixed[i] {
+			needs[i] += (width - have) / float64(free)
+		}
+	}
+}
+
+/*
+growColumns shares extra width between the columns in proportion to their
+widths, or equally if they are all empty, without growing a column past its
+MaxWidth.
+*/
+func growColumns(widths []float64, columns []*Column, auto []int, extra float64) {
+	for extra > autoWidthSlack {
+		var growable []int
+		var total float64
+		for _, i := range auto {
+			if maxWidth := columns[i].MaxWidth; maxWidth > 0 && widths[i] >= maxWidth {
+				continue
+			}
+			growable = append(growable, i)
+			total += widths[i]
+		}
+		if len(growable) == 0 {
+			return
+		}
+		left := extra
+		for _, i := range growable {
+			share := extra / float64(len(growable))
+			if total > 0 {
+				share = extra * widths[i] / total
+			}
+			if maxWidth := columns[i].MaxWidth; maxWidth > 0 && widths[i]+share > maxWidth {
+				share = maxWidth - widths[i]
+			}
+			widths[i] += share
+			left -= share
+		}
+		extra = left
+	}
+}
+
+/*
+cellContentWidths returns the narrowest width of a cell, that of its widest
+word, and its preferred width, that of its longest line, both with the
+insets of the cell.
+*/
+func (p *PDF) cellContentWidths(cell *Cell) (minWidth, prefWidth float64) {
+	_, left, right, _ := p.cellInsets(cell)
+	insets := left + right + autoWidthSlack
+	if content := cell.content(); content != nil {
+		minWidth, prefWidth = content.widths(p, cell.Style)
+		return minWidth + insets, prefWidth + insets
+	}
+	measure := p.textMeasure(cell.Style.FontStyle)
+	text := strings.TrimRight(strings.TrimSpace(cell.Text), "\n")
+	for _, para := range strings.Split(text, "\n") {
+		// Sum the words as wrapText does, so that the line fits exactly.
+		var lineWidth float64
+		for i, token := range splitWords(para) {
+			tokenWidth := measure(token)
+			lineWidth += tokenWidth
+			if i > 0 {
+				tokenWidth = measure(strings.TrimLeft(token, " "))
+			}
+			minWidth = max(minWidth, tokenWidth)
+		}
+		prefWidth = max(prefWidth, lineWidth)
+	}
+	return minWidth + insets, prefWidth + insets
+}
+
+/*
+tableContentWidths returns the narrowest and the preferred width of a table
+drawn in a cell.
+*/
+func (p *PDF) tableContentWidths(t *Table) (minWidth, prefWidth float64) {
+	rows := slices.Concat(t.Header, t.Rows, t.Footer)
+	columns := t.Columns
+	if len(columns) == 0 {
+		columns = rowColumns(rows)
+	}
+	widths := make([]float64, len(columns))
+	fixed := make([]bool, len(columns))
+	for i, column := range columns {
+		widths[i], fixed[i] = column.Width, column.Width > 0
+	}
+	minWidths, prefWidths := p.columnContentWidths(rows, widths, fixed)
+	for i := range columns {
+		if fixed[i] {
+			minWidth += widths[i]
+			prefWidth += widths[i]
+		} else {
+			minWidth += minWidths[i]
+			prefWidth += prefWidths[i]
+		}
+	}
+	return minWidth, prefWidth
+}
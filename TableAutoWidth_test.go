@@ -0,0 +1,87 @@
+package gopdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func sum(widths []float64) float64 {
+	var total float64
+	for _, w := range widths {
+		total += w
+	}
+	return total
+}
+
+func TestAutoColumns(t *testing.T) {
+	p := newTestPDF()
+	long := "a description that is long enough to need most of the table"
+	rows := [][]*Cell{
+		{NewCell("ID", nil, 0, 0), NewCell(long, nil, 0, 0), NewCell("Fixed", nil, 30, 0)},
+		{NewCell("1", nil, 0, 0), NewCell("short", nil, 0, 0), NewCell("", nil, 30, 0)},
+	}
+	widths := p.autoColumns(rows, nil, 180)
+	if !near(sum(widths), 180) {
+		t.Errorf("widths %v add up to %.2f, want 180", widths, sum(widths))
+	}
+	if widths[2] != 30 {
+		t.Errorf("fixed column is %.2f wide, want 30", widths[2])
+	}
+	if widths[0] >= widths[1] {
+		t.Errorf("widths %v, want the long column wider", widths)
+	}
+
+	// The text fits on one line, so the table has one line per row.
+	p.WriteTableRows(&Table{Rows: rows, AutoWidth: true})
+	for _, d := range texts(pageOutput(t, p)[0]) {
+		if strings.HasPrefix(long, d.Text) && d.Text != long {
+			t.Errorf("the long text is wrapped at %q", d.Text)
+		}
+	}
+}
+
+func TestAutoColumnsBounds(t *testing.T) {
+	p := newTestPDF()
+	rows := [][]*Cell{{NewCell("a", nil, 0, 0), NewCell("b", nil, 0, 0)}}
+	wide := NewColumn("", 0, 0)
+	wide.SetMinWidth(120)
+	narrow := NewColumn("", 0, 0)
+	narrow.SetMaxWidth(20)
+	widths := p.autoColumns(rows, []*Column{wide, narrow}, 180)
+	if widths[0] < 120 || widths[1] > 20 {
+		t.Errorf("widths %v do not keep the bounds", widths)
+	}
+
+	// Words wider than the table are shrunk in proportion.
+	word := strings.Repeat("x", 200)
+	widths = p.autoColumns([][]*Cell{{NewCell(word, nil, 0, 0), NewCell(word, nil, 0, 0)}}, nil, 100)
+	if !near(widths[0], 50) || !near(widths[1], 50) {
+		t.Errorf("widths %v, want the width shared equally", widths)
+	}
+}
+
+func TestAutoColumnsSpans(t *testing.T) {
+	p := newTestPDF()
+	span := NewCell(strings.Repeat("wide ", 20), nil, 0, 0)
+	span.SetColSpan(2)
+	rows := [][]*Cell{{span, NewCell("c", nil, 0, 0)}, {NewCell("a", nil, 0, 0), NewCell("b", nil, 0, 0), NewCell("c", nil, 0, 0)}}
+	widths := p.autoColumns(rows, nil, 180)
+	if widths[0]+widths[1] <= widths[2] {
+		t.Errorf("widths %v, want the spanned columns to hold the spanning text", widths)
+	}
+}
+
+func TestTableWriterAutoWidthSamplesRows(t *testing.T) {
+	p := newTestPDF()
+	w := p.NewTableWriter(&Table{AutoWidth: true})
+	w.WriteRow(NewCell("1", nil, 0, 0), NewCell("a long line of text in the second column", nil, 0, 0))
+	if w.columns != nil || len(w.rows) != 1 {
+		t.Fatalf("the first row was written before the columns were sized")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if w.columns[0] >= w.columns[1] {
+		t.Errorf("widths %v, want the long column wider", w.columns)
+	}
+}
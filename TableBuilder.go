@@ -0,0 +1,401 @@
+package gopdf
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func NewColumn(title string, width float64, widthPercentage float64) *Column {
+	return &Column{
+		Title:        title,
+		Width:        width,
+		WidthPercent: widthPercentage,
+	}
+}
+
+/*
+Column describes a column of a Table. Columns set the widths of a table
+instead of its first row, and TableBuilder uses them to build cells.
+Key names the field the column shows when importing CSV or JSON lines,
+and defaults to the title. MinWidth and MaxWidth bound the width of a column
+sized to its content in a table with AutoWidth.
+*/
+type Column struct {
+	Title        string  `json:"title"`
+	Key          string  `json:"key"`
+	Width        float64 `json:"width"`
+	WidthPercent float64 `json:"width_percent"`
+	MinWidth     float64 `json:"min_width"`
+	MaxWidth     float64 `json:"max_width"`
+	HAlign       string  `json:"h_align"`
+	Format       string  `json:"format"`
+}
+
+func (c *Column) SetKey(key string) {
+	c.Key = key
+}
+
+func (c *Column) SetMinWidth(width float64) {
+	c.MinWidth = width
+}
+
+func (c *Column) SetMaxWidth(width float64) {
+	c.MaxWidth = width
+}
+
+func (c *Column) key() string {
+	if c.Key != "" {
+		return c.Key
+	}
+	return c.Title
+}
+
+/*
+FormatValue formats a value for a cell of the column. Values are formatted
+with fmt.Sprintf when Format holds a verb that suits them, such as "%.2f"
+for numbers, and with fmt.Sprint otherwise. Times are formatted with Format
+as a layout, or as a date by default. Nil values are empty.
+*/
+func (c *Column) FormatValue(v any) string {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return ""
+	}
+	v = rv.Interface()
+	if t, ok := v.(time.Time); ok {
+		if t.IsZero() {
+			return ""
+		}
+		if c.Format != "" && !strings.Contains(c.Format, "%") {
+			return t.Format(c.Format)
+		}
+		if c.Format == "" {
+			return t.Format(time.DateOnly)
+		}
+	}
+	if arg, ok := formatArg(formatVerb(c.Format), v); ok {
+		return fmt.Sprintf(c.Format, arg)
+	}
+	return fmt.Sprint(v)
+}
+
+/*
+formatArg returns v as the operand of a fmt verb, converting integers for
+floating-point verbs, and reports whether the verb can format it.
+*/
+func formatArg(verb rune, v any) (any, bool) {
+	if verb == 0 || verb == 'v' {
+		return v, verb == 'v'
+	}
+	float := strings.ContainsRune("eEfFgG", verb)
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if float {
+			return float64(rv.Int()), true
+		}
+		return v, strings.ContainsRune("bcdoOqxXU", verb)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if float {
+			return float64(rv.Uint()), true
+		}
+		return v, strings.ContainsRune("bcdoOqxXU", verb)
+	case reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return v, float || strings.ContainsRune("bxX", verb)
+	case reflect.String:
+		return v, strings.ContainsRune("sqxX", verb)
+	case reflect.Bool:
+		return v, verb == 't'
+	}
+	switch v.(type) {
+	case fmt.Stringer, error:
+		return v, strings.ContainsRune("sqxX", verb)
+	}
+	return v, false
+}
+
+func NewTableBuilder(headerStyle, cellStyle *CellStyle, padding *Padding) *TableBuilder {
+	return &TableBuilder{
+		HeaderStyle: headerStyle,
+		CellStyle:   cellStyle,
+		Padding:     padding,
+	}
+}
+
+/*
+TableBuilder builds tables with a header row from slices of structs or
+strings, styling header and body cells alike.
+*/
+type TableBuilder struct {
+	HeaderStyle *CellStyle   `json:"header_style"`
+	CellStyle   *CellStyle   `json:"cell_style"`
+	Padding     *Padding     `json:"padding"`
+	AutoWidth   bool         `json:"auto_width"`
+	Style       *TableStyle  `json:"style"`
+	Totals      *TableTotals `json:"totals"`
+}
+
+/*
+SetStyle sets the table style of built tables, layered on the header and
+cell styles.
+*/
+func (b *TableBuilder) SetStyle(style *TableStyle) {
+	b.Style = style
+}
+
+/*
+SetTotals sets the rows of totals of built tables. See Table.SetTotals.
+*/
+func (b *TableBuilder) SetTotals(totals *TableTotals) {
+	b.Totals = totals
+}
+
+/*
+SetAutoWidth sets whether built tables size their columns to their content.
+See Table.SetAutoWidth.
+*/
+func (b *TableBuilder) SetAutoWidth(autoWidth bool) {
+	b.AutoWidth = autoWidth
+}
+
+/*
+FromStructs builds a table from a slice of structs or struct pointers.
+Each exported field is a column titled by its name, unless configured by a
+"pdf" struct tag holding the title and options:
+
+	Amount float64 `pdf:"Amount,percent=25,align=right,format=%.2f"`
+	Note   string  `pdf:"-"`
+
+The options are width (in document units), percent (of the table width,
+from 0 to 100), align (left, center, right or justify) and format (see
+Column.FormatValue). The format takes the rest of the tag, commas included,
+so it must be the last option.
+*/
+func (b *TableBuilder) FromStructs(rows any) (*Table, error) {
+	rv := reflect.ValueOf(rows)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("gopdf: table rows must be a slice, not %T", rows)
+	}
+	elem := rv.Type().Elem()
+	for elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("gopdf: table rows must be structs, not %s", elem)
+	}
+	columns, fields, err := structColumns(elem)
+	if err != nil {
+		return nil, err
+	}
+	table, styles := b.newTable(columns)
+	for i := 0; i < rv.Len(); i++ {
+		row := rv.Index(i)
+		for row.Kind() == reflect.Pointer {
+			if row.IsNil() {
+				break
+			}
+			row = row.Elem()
+		}
+		values := make([]string, len(columns))
+		if row.Kind() == reflect.Struct {
+			for j, field := range fields {
+				if v, err := row.FieldByIndexErr(field); err == nil {
+					values[j] = columns[j].FormatValue(v.Interface())
+				}
+			}
+		}
+		table.AddRow(bodyRow(styles, values)...)
+	}
+	return table, nil
+}
+
+/*
+FromStrings builds a table from rows of strings, the first of which is the
+header row.
+*/
+func (b *TableBuilder) FromStrings(rows [][]string) *Table {
+	if len(rows) == 0 {
+		table, _ := b.newTable(nil)
+		return table
+	}
+	columns := make([]*Column, len(rows[0]))
+	for i, title := range rows[0] {
+		columns[i] = NewColumn(title, 0, 0)
+	}
+	table, styles := b.newTable(columns)
+	for _, row := range rows[1:] {
+		table.AddRow(bodyRow(styles, row)...)
+	}
+	return table
+}
+
+/*
+WriteStructs writes a table built from a slice of structs.
+See TableBuilder.FromStructs; a nil builder uses default styles.
+*/
+func (p *PDF) WriteStructs(rows any, builder *TableBuilder) error {
+	if builder == nil {
+		builder = NewTableBuilder(nil, nil, nil)
+	}
+	table, err := builder.FromStructs(rows)
+	if err != nil {
+		return err
+	}
+	return p.WriteTableRows(table)
+}
+
+/*
+WriteStrings writes a table built from rows of strings, the first of which
+is the header row. A nil builder uses default styles.
+*/
+func (p *PDF) WriteStrings(rows [][]string, builder *TableBuilder) error {
+	if builder == nil {
+		builder = NewTableBuilder(nil, nil, nil)
+	}
+	return p.WriteTableRows(builder.FromStrings(rows))
+}
+
+/*
+newTable returns a table with a header row for the columns, and the styles
+of the body cells of each column.
+*/
+func (b *TableBuilder) newTable(columns []*Column) (*Table, []*CellStyle) {
+	table := NewTable(b.Padding)
+	table.Columns = columns
+	table.AutoWidth = b.AutoWidth
+	table.Style = b.Style
+	table.Totals = b.Totals
+	if len(columns) == 0 {
+		return table, nil
+	}
+	defaultStyle := NewCellStyle(nil, NewBorderStyle(true, true, true, true, nil), nil, "", "")
+	headerStyle, cellStyle := b.HeaderStyle, b.CellStyle
+	if headerStyle == nil {
+		headerStyle = defaultStyle
+	}
+	if cellStyle == nil {
+		cellStyle = defaultStyle
+	}
+	header := make([]*Cell, len(columns))
+	styles := make([]*CellStyle, len(columns))
+	for i, column := range columns {
+		header[i] = NewCell(column.Title, alignedStyle(headerStyle, column.HAlign), 0, 0)
+		styles[i] = alignedStyle(cellStyle, column.HAlign)
+	}
+	table.AddHeaderRow(header...)
+	return table, styles
+}
+
+func bodyRow(styles []*CellStyle, values []string) []*Cell {
+	cells := make([]*Cell, len(styles))
+	for i, style := range styles {
+		var text string
+		if i < len(values) {
+			text = values[i]
+		}
+		cells[i] = NewCell(text, style, 0, 0)
+	}
+	return cells
+}
+
+/*
+alignedStyle returns a copy of style with the given horizontal alignment,
+or style itself if the alignment is empty.
+*/
+func alignedStyle(style *CellStyle, align string) *CellStyle {
+	if align == "" {
+		return style
+	}
+	aligned := *style
+	aligned.SetHAlign(align)
+	return &aligned
+}
+
+/*
+structColumns returns the columns of a struct type and the index of the
+field of each.
+*/
+func structColumns(t reflect.Type) ([]*Column, [][]int, error) {
+	var columns []*Column
+	var fields [][]int
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		tag := field.Tag.Get("pdf")
+		if tag == "-" {
+			continue
+		}
+		column, err := parseColumnTag(field.Name, tag)
+		if err != nil {
+			return nil, nil, fmt.Errorf("gopdf: field %s: %w", field.Name, err)
+		}
+		columns = append(columns, column)
+		fields = append(fields, field.Index)
+	}
+	if len(columns) == 0 {
+		return nil, nil, errors.New("gopdf: table rows have no exported fields")
+	}
+	return columns, fields, nil
+}
+
+func parseColumnTag(name, tag string) (*Column, error) {
+	title, options, _ := strings.Cut(tag, ",")
+	column := NewColumn(name, 0, 0)
+	if title != "" {
+		column.Title = title
+	}
+	for options != "" {
+		// The format takes the rest of the tag, as it may hold commas.
+		var option string
+		if strings.HasPrefix(strings.TrimSpace(options), "format=") {
+			option, options = options, ""
+		} else {
+			option, options, _ = strings.Cut(options, ",")
+		}
+		key, value, _ := strings.Cut(option, "=")
+		var err error
+		switch strings.TrimSpace(key) {
+		case "width":
+			column.Width, err = strconv.ParseFloat(value, 64)
+		case "percent":
+			var percent float64
+			percent, err = strconv.ParseFloat(value, 64)
+			column.WidthPercent = percent / 100
+		case "align":
+			column.HAlign, err = parseAlign(value)
+		case "format":
+			column.Format = value
+		default:
+			err = fmt.Errorf("unknown option %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return column, nil
+}
+
+func parseAlign(s string) (string, error) {
+	switch strings.ToLower(s) {
+	case "left", "l":
+		return AlignLeft, nil
+	case "center", "c":
+		return AlignCenter, nil
+	case "right", "r":
+		return AlignRight, nil
+	case "justify", "j":
+		return AlignJustify, nil
+	}
+	return "", fmt.Errorf("unknown alignment %q", s)
+}
@@ -0,0 +1,119 @@
+package gopdf
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseColumnTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want Column
+	}{
+		{"", Column{Title: "Field"}},
+		{"Amount,width=80,align=right", Column{Title: "Amount", Width: 80, HAlign: AlignRight}},
+		{",percent=25", Column{Title: "Field", WidthPercent: 0.25}},
+		{"Date,align=c,format=Jan 2, 2006", Column{Title: "Date", HAlign: AlignCenter, Format: "Jan 2, 2006"}},
+		{"Note, format=%s, %s", Column{Title: "Note", Format: "%s, %s"}},
+	}
+	for _, tt := range tests {
+		column, err := parseColumnTag("Field", tt.tag)
+		if err != nil {
+			t.Errorf("parseColumnTag(%q): %v", tt.tag, err)
+			continue
+		}
+		if *column != tt.want {
+			t.Errorf("parseColumnTag(%q) = %+v, want %+v", tt.tag, *column, tt.want)
+		}
+	}
+	for _, tag := range []string{"A,width=wide", "A,percent=", "A,align=top", "A,size=3"} {
+		if _, err := parseColumnTag("Field", tag); err == nil {
+			t.Errorf("parseColumnTag(%q) succeeded", tag)
+		}
+	}
+}
+
+func TestColumnFormatValue(t *testing.T) {
+	date := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	amount := 12.5
+	tests := []struct {
+		format string
+		value  any
+		want   string
+	}{
+		{"", 12, "12"},
+		{"%.2f", amount, "12.50"},
+		{"%.2f", &amount, "12.50"},
+		{"", (*float64)(nil), ""},
+		{"", nil, ""},
+		{"", date, "2024-03-05"},
+		{"Jan 2, 2006", date, "Mar 5, 2024"},
+		{"", time.Time{}, ""},
+		{"%.1f", 3, "3.0"},
+		{"%.1f%%", 12.34, "12.3%"},
+		{"%05d", 42, "00042"},
+		{"%d", 12.5, "12.5"},
+		{"%s", 12, "12"},
+		{"n/a", 12, "12"},
+		{"%t", true, "true"},
+	}
+	for _, tt := range tests {
+		column := &Column{Format: tt.format}
+		if got := column.FormatValue(tt.value); got != tt.want {
+			t.Errorf("FormatValue(%v) with %q = %q, want %q", tt.value, tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestFromStructs(t *testing.T) {
+	type row struct {
+		Name   string
+		Amount float64 `pdf:"Total,percent=50,align=right,format=%.2f"`
+		Secret string  `pdf:"-"`
+		hidden int
+	}
+	table, err := NewTableBuilder(nil, nil, nil).FromStructs([]*row{{Name: "a", Amount: 1}, nil, {Name: "b", Amount: 2.5}})
+	if err != nil {
+		t.Fatalf("FromStructs: %v", err)
+	}
+	if len(table.Columns) != 2 || table.Columns[1].Title != "Total" || table.Columns[1].WidthPercent != 0.5 {
+		t.Fatalf("columns = %+v", table.Columns)
+	}
+	if got := table.Header[0][1].Text; got != "Total" {
+		t.Errorf("header = %q", got)
+	}
+	want := [][]string{{"a", "1.00"}, {"", ""}, {"b", "2.50"}}
+	for i, cells := range table.Rows {
+		if cells[0].Text != want[i][0] || cells[1].Text != want[i][1] {
+			t.Errorf("row %d = %q, %q, want %q", i, cells[0].Text, cells[1].Text, want[i])
+		}
+		if cells[1].Style.HAlign != AlignRight {
+			t.Errorf("row %d is not aligned right", i)
+		}
+	}
+
+	p := newTestPDF()
+	p.WriteTableRows(table)
+	if w := p.tableWidths(table, nil, 100); w[1] != 50 {
+		t.Errorf("percent=50 column is %v wide in a table of 100", w[1])
+	}
+
+	for _, rows := range []any{row{}, []int{1}, []struct{ x int }{{}}} {
+		if _, err := NewTableBuilder(nil, nil, nil).FromStructs(rows); err == nil {
+			t.Errorf("FromStructs(%T) succeeded", rows)
+		}
+	}
+}
+
+func TestFromStrings(t *testing.T) {
+	table := NewTableBuilder(nil, nil, nil).FromStrings([][]string{{"A", "B"}, {"1"}, {"2", "3"}})
+	if len(table.Header) != 1 || table.Header[0][1].Text != "B" {
+		t.Errorf("header = %+v", table.Header)
+	}
+	if len(table.Rows) != 2 || len(table.Rows[0]) != 2 || table.Rows[0][1].Text != "" || table.Rows[1][1].Text != "3" {
+		t.Errorf("rows are not filled to the columns")
+	}
+	if table := NewTableBuilder(nil, nil, nil).FromStrings(nil); len(table.Header) != 0 {
+		t.Errorf("empty rows built a header")
+	}
+}
@@ -0,0 +1,324 @@
+package gopdf
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+func NewCSVOptions(delimiter rune, header bool, columns ...*Column) *CSVOptions {
+	return &CSVOptions{
+		Delimiter: delimiter,
+		Header:    header,
+		Columns:   columns,
+	}
+}
+
+/*
+CSVOptions configures WriteCSV.
+Delimiter separates fields and defaults to a comma. Header tells that the
+first record holds the column titles. Columns selects and orders the
+columns by the titles of the header record, matched with their keys;
+without a header record, they describe the fields in order. All fields are
+shown if Columns is empty.
+*/
+type CSVOptions struct {
+	Delimiter  rune      `json:"delimiter"`
+	Header     bool      `json:"header"`
+	Columns    []*Column `json:"columns"`
+	LazyQuotes bool      `json:"lazy_quotes"`
+}
+
+func (o *CSVOptions) SetDelimiter(delimiter rune) {
+	o.Delimiter = delimiter
+}
+
+func (o *CSVOptions) SetHeader(header bool) {
+	o.Header = header
+}
+
+func (o *CSVOptions) SetColumns(columns ...*Column) {
+	o.Columns = columns
+}
+
+func (o *CSVOptions) SetLazyQuotes(lazyQuotes bool) {
+	o.LazyQuotes = lazyQuotes
+}
+
+/*
+WriteCSV writes a table of the CSV records read from r, one row at a time,
+so that the input is never held in memory. Nil options read comma separated
+records with a header record, and a nil builder uses default styles.
+*/
+func (p *PDF) WriteCSV(r io.Reader, opts *CSVOptions, builder *TableBuilder) error {
+	if err := p.Err(); err != nil {
+		return err
+	}
+	if opts == nil {
+		opts = NewCSVOptions(',', true)
+	}
+	if builder == nil {
+		builder = NewTableBuilder(nil, nil, nil)
+	}
+	reader := csv.NewReader(r)
+	if opts.Delimiter != 0 {
+		reader.Comma = opts.Delimiter
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = opts.LazyQuotes
+	reader.ReuseRecord = true
+
+	record, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("gopdf: reading CSV: %w", err)
+	}
+	columns, fields, err := csvColumns(opts, record)
+	if err != nil {
+		return err
+	}
+	table, styles := builder.newTable(columns)
+	if !opts.Header {
+		table.Header = nil
+	}
+	w := p.NewTableWriter(table)
+	if !opts.Header {
+		if err := w.WriteRow(bodyRow(styles, csvValues(columns, fields, record))...); err != nil {
+			return err
+		}
+	}
+	for {
+		record, err = reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("gopdf: reading CSV: %w", err)
+		}
+		if err := w.WriteRow(bodyRow(styles, csvValues(columns, fields, record))...); err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
+
+/*
+csvColumns returns the columns of a CSV table and the field of each,
+from the options and the first record.
+*/
+func csvColumns(opts *CSVOptions, first []string) ([]*Column, []int, error) {
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = make([]*Column, len(first))
+		for i, field := range first {
+			title := ""
+			if opts.Header {
+				title = field
+			}
+			columns[i] = NewColumn(title, 0, 0)
+		}
+	}
+	fields := make([]int, len(columns))
+	for i, column := range columns {
+		fields[i] = i
+		if !opts.Header || len(opts.Columns) == 0 {
+			continue
+		}
+		fields[i] = -1
+		for j, field := range first {
+			if field == column.key() {
+				fields[i] = j
+				break
+			}
+		}
+		if fields[i] < 0 {
+			return nil, nil, fmt.Errorf("gopdf: CSV has no column %q", column.key())
+		}
+	}
+	return columns, fields, nil
+}
+
+/*
+csvValues formats the fields of a record for the columns. Fields of
+columns with a format are formatted as numbers if they are numbers.
+*/
+func csvValues(columns []*Column, fields []int, record []string) []string {
+	values := make([]string, len(columns))
+	for i, field := range fields {
+		if field >= len(record) {
+			continue
+		}
+		if columns[i].Format == "" {
+			values[i] = record[field]
+			continue
+		}
+		values[i] = columns[i].FormatValue(parseNumber(record[field], columns[i].Format))
+	}
+	return values
+}
+
+/*
+WriteJSONLines writes a table of the JSON objects read from r, one row at a
+time, so that the input is never held in memory. Columns select the fields
+of the objects by key; if none are given, the columns are the fields of the
+first object, in order. Numbers are shown as written, or formatted as such
+by the column format, and nested objects and arrays are shown as JSON.
+A nil builder uses default styles.
+*/
+func (p *PDF) WriteJSONLines(r io.Reader, columns []*Column, builder *TableBuilder) error {
+	if err := p.Err(); err != nil {
+		return err
+	}
+	if builder == nil {
+		builder = NewTableBuilder(nil, nil, nil)
+	}
+	dec := json.NewDecoder(r)
+	var w *TableWriter
+	var styles []*CellStyle
+	for n := 1; ; n++ {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("gopdf: reading JSON record %d: %w", n, err)
+		}
+		var record map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &record); err != nil {
+			return fmt.Errorf("gopdf: reading JSON record %d: %w", n, err)
+		}
+		if w == nil {
+			if len(columns) == 0 {
+				keys, err := jsonKeys(raw)
+				if err != nil {
+					return fmt.Errorf("gopdf: reading JSON record %d: %w", n, err)
+				}
+				for _, key := range keys {
+					columns = append(columns, NewColumn(key, 0, 0))
+				}
+			}
+			var table *Table
+			table, styles = builder.newTable(columns)
+			w = p.NewTableWriter(table)
+		}
+		values := make([]string, len(columns))
+		for i, column := range columns {
+			if v, ok := record[column.key()]; ok {
+				values[i] = column.FormatValue(jsonValue(v, column.Format))
+			}
+		}
+		if err := w.WriteRow(bodyRow(styles, values)...); err != nil {
+			return err
+		}
+	}
+	if w == nil {
+		return nil
+	}
+	return w.Close()
+}
+
+/*
+jsonKeys returns the keys of a JSON object in the order they appear.
+*/
+func jsonKeys(raw json.RawMessage) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if t, err := dec.Token(); err != nil {
+		return nil, err
+	} else if t != json.Delim('{') {
+		return nil, errors.New("not an object")
+	}
+	var keys []string
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, t.(string))
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+/*
+jsonValue decodes a JSON value for Column.FormatValue with the given format.
+Numbers are kept as written unless the format needs them parsed, so that
+large integers and trailing zeros are shown as in the input.
+*/
+func jsonValue(raw json.RawMessage, format string) any {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return nil
+	}
+	switch raw[0] {
+	case '{', '[':
+		var b bytes.Buffer
+		if err := json.Compact(&b, raw); err != nil {
+			return string(raw)
+		}
+		return b.String()
+	case 'n':
+		return nil
+	case 't', 'f':
+		return raw[0] == 't'
+	case '"':
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return string(raw)
+		}
+		return s
+	}
+	if format == "" {
+		return json.Number(raw)
+	}
+	return parseNumber(string(raw), format)
+}
+
+/*
+parseNumber returns s as a number if it is one, or else s. Integers are
+int64 unless format has a floating-point verb, and other numbers float64.
+NaN and infinities are not numbers.
+*/
+func parseNumber(s string, format string) any {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if verb := formatVerb(format); verb == 0 || !strings.ContainsRune("eEfFgG", verb) {
+			return i
+		}
+		return float64(i)
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+		return f
+	}
+	return s
+}
+
+/*
+formatVerb returns the verb of the last directive of a fmt format, or 0.
+*/
+func formatVerb(format string) rune {
+	var verb rune
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		// Skip the flags, width and precision up to the verb, and %%.
+		for i++; i < len(format); i++ {
+			if c := format[i]; c == '%' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' {
+				if c != '%' {
+					verb = rune(c)
+				}
+				break
+			}
+		}
+	}
+	return verb
+}
@@ -0,0 +1,122 @@
+package gopdf
+
+import (
+	"strings"
+	"testing"
+)
+
+/*
+bodyTexts returns the text of the body rows of the first page, after a
+header row of n columns.
+*/
+func bodyTexts(t *testing.T, p *PDF, n int) []string {
+	t.Helper()
+	var out []string
+	for _, d := range texts(pageOutput(t, p)[0])[n:] {
+		out = append(out, d.Text)
+	}
+	return out
+}
+
+func TestWriteCSV(t *testing.T) {
+	input := "id;name;amount\n1;Anna;12.5\n2;\"Bo; Jr\";3\n"
+	amount := NewColumn("Amount", 0, 0)
+	amount.SetKey("amount")
+	amount.Format = "%.2f"
+	opts := NewCSVOptions(';', true, amount, NewColumn("name", 0, 0))
+	p := newTestPDF()
+	if err := p.WriteCSV(strings.NewReader(input), opts, nil); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	got := strings.Join(bodyTexts(t, p, 2), "|")
+	if want := "12.50|Anna|3.00|Bo; Jr"; got != want {
+		t.Errorf("rows = %q, want %q", got, want)
+	}
+
+	opts = NewCSVOptions(',', false)
+	p = newTestPDF()
+	if err := p.WriteCSV(strings.NewReader("a,b\nc\n"), opts, nil); err != nil {
+		t.Fatalf("WriteCSV without header: %v", err)
+	}
+	if got := strings.Join(bodyTexts(t, p, 0), "|"); got != "a|b|c" {
+		t.Errorf("rows without header = %q", got)
+	}
+
+	// Values that only parse as non-finite numbers are shown as written.
+	opts = NewCSVOptions(',', true, amount)
+	p = newTestPDF()
+	if err := p.WriteCSV(strings.NewReader("amount\nNaN\nInf\n-Infinity\n2\n"), opts, nil); err != nil {
+		t.Fatalf("WriteCSV of non-finite values: %v", err)
+	}
+	if got, want := strings.Join(bodyTexts(t, p, 1), "|"), "NaN|Inf|-Infinity|2.00"; got != want {
+		t.Errorf("non-finite rows = %q, want %q", got, want)
+	}
+}
+
+func TestWriteCSVErrors(t *testing.T) {
+	opts := NewCSVOptions(',', true, NewColumn("missing", 0, 0))
+	if err := newTestPDF().WriteCSV(strings.NewReader("a,b\n1,2\n"), opts, nil); err == nil {
+		t.Errorf("WriteCSV with an unknown column succeeded")
+	}
+	if err := newTestPDF().WriteCSV(strings.NewReader("a\n\"open\n"), nil, nil); err == nil {
+		t.Errorf("WriteCSV of malformed input succeeded")
+	}
+	if err := newTestPDF().WriteCSV(strings.NewReader(""), nil, nil); err != nil {
+		t.Errorf("WriteCSV of empty input: %v", err)
+	}
+}
+
+func TestWriteJSONLines(t *testing.T) {
+	input := `{"id": 12345678901234567890, "price": 0.10, "tags": ["a", 1.50], "ok": true, "note": null, "name": "Aé"}
+{"id": 2, "price": 3, "name": "B"}
+`
+	p := newTestPDF()
+	if err := p.WriteJSONLines(strings.NewReader(input), nil, nil); err != nil {
+		t.Fatalf("WriteJSONLines: %v", err)
+	}
+	// The columns are the fields of the first object.
+	if got := strings.Join(bodyTexts(t, p, 0)[:6], "|"); got != "id|price|tags|ok|note|name" {
+		t.Errorf("header = %q", got)
+	}
+
+	// Numbers are shown as written.
+	tests := map[string]string{
+		"12345678901234567890": "12345678901234567890",
+		"0.10":                 "0.10",
+		`["a", 1.50]`:          `["a",1.50]`,
+		"true":                 "true",
+		"null":                 "",
+		`"Aé"`:                 "Aé",
+	}
+	column := NewColumn("", 0, 0)
+	for raw, want := range tests {
+		if got := column.FormatValue(jsonValue([]byte(raw), "")); got != want {
+			t.Errorf("value of %s = %q, want %q", raw, got, want)
+		}
+	}
+
+	price := NewColumn("Price", 0, 0)
+	price.SetKey("price")
+	price.Format = "%.2f"
+	id := NewColumn("ID", 0, 0)
+	id.SetKey("id")
+	id.Format = "%05d"
+	input = `{"id": 7, "price": 0.1}
+{"price": 3, "id": 12}
+`
+	p = newTestPDF()
+	if err := p.WriteJSONLines(strings.NewReader(input), []*Column{id, price}, nil); err != nil {
+		t.Fatalf("WriteJSONLines with columns: %v", err)
+	}
+	if got := strings.Join(bodyTexts(t, p, 2), "|"); got != "00007|0.10|00012|3.00" {
+		t.Errorf("formatted rows = %q", got)
+	}
+}
+
+func TestWriteJSONLinesErrors(t *testing.T) {
+	for _, input := range []string{`{"a": 1} {"a":`, `[1, 2]`} {
+		if err := newTestPDF().WriteJSONLines(strings.NewReader(input), nil, nil); err == nil {
+			t.Errorf("WriteJSONLines(%q) succeeded", input)
+		}
+	}
+}
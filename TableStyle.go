@@ -0,0 +1,340 @@
+package gopdf
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+func NewTableStyle(header, footer *StyleLayer, rowFills ...*RGB) *TableStyle {
+	return &TableStyle{
+		Header:   header,
+		Footer:   footer,
+		RowFills: rowFills,
+	}
+}
+
+/*
+TableStyle styles the cells of a Table on top of their own CellStyle.
+Header and Footer style the header and footer rows, and Body the others,
+which are then filled with RowFills in turn, so that two colors stripe
+them; a nil color leaves a row unfilled. Columns style the body cells of
+each column, and Rules style the body cells whose text matches their
+condition, the last matching rule applying last.
+*/
+type TableStyle struct {
+	Header   *StyleLayer   `json:"header"`
+	Footer   *StyleLayer   `json:"footer"`
+	Body     *StyleLayer   `json:"body"`
+	RowFills []*RGB        `json:"row_fills"`
+	Columns  []*StyleLayer `json:"columns"`
+	Rules    []*CellRule   `json:"rules"`
+}
+
+/*
+NewTableTheme returns the table style of a theme, one of TableThemeGrid,
+TableThemeStriped or TableThemeMinimal. By default, it is the grid theme.
+*/
+func NewTableTheme(theme string) *TableStyle {
+	gray := NewRGB(230, 230, 230)
+	switch theme {
+	case TableThemeStriped:
+		header := NewStyleLayer(nil, NewBorderStyle(false, false, false, true, nil), nil, "", "")
+		header.SetBold(true)
+		footer := NewStyleLayer(nil, NewBorderStyle(true, false, false, false, nil), nil, "", "")
+		footer.SetBold(true)
+		return NewTableStyle(header, footer, nil, NewRGB(245, 245, 245))
+	case TableThemeMinimal:
+		header := NewStyleLayer(nil, NewBorderStyle(false, false, false, true, nil), nil, "", "")
+		header.SetBold(true)
+		footer := NewStyleLayer(nil, NewBorderStyle(true, false, false, false, nil), nil, "", "")
+		return NewTableStyle(header, footer)
+	default:
+		grid := NewBorderStyle(true, true, true, true, nil)
+		header := NewStyleLayer(nil, grid, gray, "", "")
+		header.SetBold(true)
+		footer := NewStyleLayer(nil, grid, gray, "", "")
+		style := NewTableStyle(header, footer)
+		style.Body = NewStyleLayer(nil, grid, nil, "", "")
+		return style
+	}
+}
+
+/*
+SetColumn styles the body cells of a column, counted from 0.
+A negative column is ignored.
+*/
+func (s *TableStyle) SetColumn(col int, layer *StyleLayer) {
+	if col < 0 {
+		return
+	}
+	for len(s.Columns) <= col {
+		s.Columns = append(s.Columns, nil)
+	}
+	s.Columns[col] = layer
+}
+
+func (s *TableStyle) AddRule(rule *CellRule) {
+	s.Rules = append(s.Rules, rule)
+}
+
+func NewStyleLayer(fontStyle *FontStyle, borderStyle *BorderStyle, fillColor *RGB, hAlign, vAlign string) *StyleLayer {
+	return &StyleLayer{
+		FontStyle:   fontStyle,
+		BorderStyle: borderStyle,
+		FillColor:   fillColor,
+		HAlign:      hAlign,
+		VAlign:      vAlign,
+	}
+}
+
+/*
+StyleLayer changes parts of a CellStyle. Nil and empty fields keep those of
+the style beneath. FontColor, Bold and Italic change the font style, whether
+the layer's or the one beneath.
+*/
+type StyleLayer struct {
+	FontStyle   *FontStyle   `json:"font_style"`
+	FontColor   *RGB         `json:"font_color"`
+	Bold        bool         `json:"bold"`
+	Italic      bool         `json:"italic"`
+	BorderStyle *BorderStyle `json:"border_style"`
+	FillColor   *RGB         `json:"fill_color"`
+	HAlign      string       `json:"h_align"`
+	VAlign      string       `json:"v_align"`
+	Padding     *Padding     `json:"padding"`
+}
+
+func (l *StyleLayer) SetFontColor(color *RGB) {
+	l.FontColor = color
+}
+
+func (l *StyleLayer) SetBold(bold bool) {
+	l.Bold = bold
+}
+
+func (l *StyleLayer) SetItalic(italic bool) {
+	l.Italic = italic
+}
+
+func (l *StyleLayer) SetPadding(padding *Padding) {
+	l.Padding = padding
+}
+
+/*
+apply returns a copy of style changed by the layer.
+*/
+func (l *StyleLayer) apply(style *CellStyle) *CellStyle {
+	s := *style
+	if l.FontStyle != nil {
+		s.FontStyle = l.FontStyle
+	}
+	if l.FontColor != nil || l.Bold || l.Italic {
+		fontStyle := *s.FontStyle
+		if l.FontColor != nil {
+			fontStyle.FontColor = l.FontColor
+		}
+		fontStyle.Bold = fontStyle.Bold || l.Bold
+		fontStyle.Italic = fontStyle.Italic || l.Italic
+		s.FontStyle = &fontStyle
+	}
+	if l.BorderStyle != nil {
+		s.BorderStyle = l.BorderStyle
+	}
+	if l.FillColor != nil {
+		s.FillColor = l.FillColor
+	}
+	if l.HAlign != "" {
+		s.SetHAlign(l.HAlign)
+	}
+	if l.VAlign != "" {
+		s.SetVAlign(l.VAlign)
+	}
+	if l.Padding != nil {
+		s.Padding = l.Padding
+	}
+	return &s
+}
+
+/*
+CellCondition reports whether the text of a cell matches a rule.
+*/
+type CellCondition func(text string) bool
+
+func NewCellRule(condition CellCondition, layer *StyleLayer, columns ...int) *CellRule {
+	return &CellRule{
+		Condition: condition,
+		Layer:     layer,
+		Columns:   columns,
+	}
+}
+
+/*
+CellRule styles the body cells matching its condition with its layer.
+If Columns is set, the rule only applies to those columns, counted from 0.
+*/
+type CellRule struct {
+	Condition CellCondition `json:"-"`
+	Layer     *StyleLayer   `json:"layer"`
+	Columns   []int         `json:"columns"`
+}
+
+func (r *CellRule) matches(col int, text string) bool {
+	if r.Condition == nil || r.Layer == nil {
+		return false
+	}
+	if len(r.Columns) > 0 {
+		var in bool
+		for _, c := range r.Columns {
+			in = in || c == col
+		}
+		if !in {
+			return false
+		}
+	}
+	return r.Condition(text)
+}
+
+/*
+ValueNegative matches cells holding a negative number.
+*/
+func ValueNegative() CellCondition {
+	return ValueLessThan(0)
+}
+
+/*
+ValueGreaterThan matches cells holding a number greater than n.
+*/
+func ValueGreaterThan(n float64) CellCondition {
+	return func(text string) bool {
+		v, ok := cellNumber(text)
+		return ok && v > n
+	}
+}
+
+/*
+ValueLessThan matches cells holding a number less than n.
+*/
+func ValueLessThan(n float64) CellCondition {
+	return func(text string) bool {
+		v, ok := cellNumber(text)
+		return ok && v < n
+	}
+}
+
+/*
+ValueEquals matches cells whose text is s, ignoring surrounding spaces.
+*/
+func ValueEquals(s string) CellCondition {
+	return func(text string) bool {
+		return strings.TrimSpace(text) == s
+	}
+}
+
+/*
+cellNumber parses the number in the text of a cell, allowing currency
+symbols, thousands separators of commas or spaces and a trailing percent
+sign, as in "$1,200.00" or "12 %". NaN and infinities are not numbers.
+*/
+func cellNumber(text string) (float64, bool) {
+	text = strings.TrimSpace(text)
+	text = strings.TrimSuffix(text, "%")
+	text = strings.Map(func(r rune) rune {
+		if r == ',' || unicode.IsSpace(r) || unicode.Is(unicode.Sc, r) {
+			return -1
+		}
+		return r
+	}, text)
+	v, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, false
+	}
+	return v, true
+}
+
+/*
+styleKey identifies a style resolved for a cell, so that cells styled alike
+share it.
+*/
+type styleKey struct {
+	base  *CellStyle
+	part  tablePart
+	col   int
+	fill  int
+	rules string
+}
+
+type tablePart int
+
+const (
+	tableBody tablePart = iota
+	tableHeader
+	tableFooter
+)
+
+/*
+styleBlock replaces the cells of a block with copies styled by the table
+style. Body rows are counted from firstRow for the row fills.
+*/
+func (s *TableStyle) styleBlock(block *tableBlock, part tablePart, firstRow int, cache map[styleKey]*CellStyle) {
+	if s == nil {
+		return
+	}
+	for i, c := range block.cells {
+		key := styleKey{base: c.Cell.Style, part: part, col: -1, fill: -1}
+		if part == tableBody {
+			key.col = c.Col
+			if len(s.RowFills) > 0 {
+				key.fill = (firstRow + c.Row) % len(s.RowFills)
+			}
+			var rules []byte
+			for r, rule := range s.Rules {
+				if rule.matches(c.Col, c.Cell.Text) {
+					rules = strconv.AppendInt(append(rules, ','), int64(r), 10)
+				}
+			}
+			key.rules = string(rules)
+		}
+		style, ok := cache[key]
+		if !ok {
+			style = s.resolve(key)
+			cache[key] = style
+		}
+		if style != c.Cell.Style {
+			cell := *c.Cell
+			cell.Style = style
+			block.cells[i].Cell = &cell
+		}
+	}
+}
+
+func (s *TableStyle) resolve(key styleKey) *CellStyle {
+	style := key.base
+	switch key.part {
+	case tableHeader:
+		if s.Header != nil {
+			style = s.Header.apply(style)
+		}
+		return style
+	case tableFooter:
+		if s.Footer != nil {
+			style = s.Footer.apply(style)
+		}
+		return style
+	}
+	if s.Body != nil {
+		style = s.Body.apply(style)
+	}
+	if key.fill >= 0 && s.RowFills[key.fill] != nil {
+		style = (&StyleLayer{FillColor: s.RowFills[key.fill]}).apply(style)
+	}
+	if key.col < len(s.Columns) && s.Columns[key.col] != nil {
+		style = s.Columns[key.col].apply(style)
+	}
+	for _, r := range strings.Split(key.rules, ",")[1:] {
+		i, _ := strconv.Atoi(r)
+		style = s.Rules[i].Layer.apply(style)
+	}
+	return style
+}
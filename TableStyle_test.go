@@ -0,0 +1,118 @@
+package gopdf
+
+import "testing"
+
+func TestTableStyleLayers(t *testing.T) {
+	red, gray, white := NewRGB(255, 0, 0), NewRGB(230, 230, 230), NewRGB(255, 255, 255)
+	header := NewStyleLayer(nil, nil, nil, AlignCenter, "")
+	header.SetBold(true)
+	style := NewTableStyle(header, nil, gray, nil)
+	amount := NewStyleLayer(nil, nil, nil, AlignRight, "")
+	style.SetColumn(1, amount)
+	style.SetColumn(-1, amount)
+	negative := NewStyleLayer(nil, nil, nil, "", "")
+	negative.SetFontColor(red)
+	style.AddRule(NewCellRule(ValueNegative(), negative, 1))
+	style.AddRule(NewCellRule(ValueEquals("total"), NewStyleLayer(nil, nil, white, "", "")))
+
+	base := NewCellStyle(nil, nil, nil, "", "")
+	cell := func(text string) *Cell { return NewCell(text, base, 0, 0) }
+	cache := map[styleKey]*CellStyle{}
+
+	head, _ := placeCells([][]*Cell{{cell("Name"), cell("Amount")}}, 2)
+	style.styleBlock(head, tableHeader, 0, cache)
+	if s := head.cells[1].Cell.Style; !s.FontStyle.Bold || s.HAlign != AlignCenter {
+		t.Errorf("header cell is not styled by the header layer")
+	}
+	if base.FontStyle.Bold || base.HAlign != AlignLeft {
+		t.Errorf("the base style was changed")
+	}
+
+	body, _ := placeCells([][]*Cell{
+		{cell("a"), cell("-5")},
+		{cell("-1"), cell("5")},
+		{cell("total"), cell("0")},
+	}, 2)
+	style.styleBlock(body, tableBody, 0, cache)
+	styles := make([]*CellStyle, len(body.cells))
+	for i, c := range body.cells {
+		styles[i] = c.Cell.Style
+	}
+	if styles[0].FillColor != gray || styles[2].FillColor != nil || styles[5].FillColor != gray {
+		t.Errorf("rows are not striped")
+	}
+	if styles[1].HAlign != AlignRight || styles[0].HAlign != AlignLeft {
+		t.Errorf("the column layer is not applied to its column only")
+	}
+	if styles[1].FontStyle.FontColor != red || styles[2].FontStyle.FontColor == red {
+		t.Errorf("the negative rule is not applied to its column only")
+	}
+	if styles[4].FillColor != white {
+		t.Errorf("the last matching rule does not apply last")
+	}
+	again, _ := placeCells([][]*Cell{{cell("b")}}, 2)
+	style.styleBlock(again, tableBody, 2, cache)
+	if again.cells[0].Cell.Style != styles[0] {
+		t.Errorf("cells styled alike do not share a style")
+	}
+}
+
+func TestTableStyleRowFillsContinueAcrossBlocks(t *testing.T) {
+	gray := NewRGB(230, 230, 230)
+	style := NewTableStyle(nil, nil, gray, nil)
+	block, _ := placeCells([][]*Cell{{NewCell("b", nil, 0, 0)}}, 1)
+	style.styleBlock(block, tableBody, 1, map[styleKey]*CellStyle{})
+	if block.cells[0].Cell.Style.FillColor != nil {
+		t.Errorf("the second body row is filled like the first")
+	}
+}
+
+func TestSetColumnNegative(t *testing.T) {
+	style := NewTableStyle(nil, nil)
+	style.SetColumn(-3, NewStyleLayer(nil, nil, nil, AlignRight, ""))
+	style.SetColumn(2, NewStyleLayer(nil, nil, nil, AlignRight, ""))
+	if len(style.Columns) != 3 || style.Columns[2] == nil {
+		t.Errorf("Columns = %v", style.Columns)
+	}
+}
+
+func TestValueConditionsIgnoreNonFinite(t *testing.T) {
+	for _, text := range []string{"NaN", "-Inf", "infinity", "-1e400"} {
+		if ValueNegative()(text) || ValueGreaterThan(0)(text) {
+			t.Errorf("%q matches a value condition", text)
+		}
+	}
+}
+
+func TestTableThemes(t *testing.T) {
+	for _, theme := range []string{TableThemeGrid, TableThemeStriped, TableThemeMinimal, ""} {
+		p := newTestPDF()
+		table := testTable(3)
+		table.SetStyle(NewTableTheme(theme))
+		if err := p.WriteTableRows(table); err != nil {
+			t.Errorf("%q: WriteTableRows: %v", theme, err)
+		}
+	}
+}
+
+func TestTableStyleCacheIsClearedPerPage(t *testing.T) {
+	p := newTestPDF()
+	table := NewTable(nil)
+	table.SetStyle(NewTableStyle(nil, nil, NewRGB(230, 230, 230), nil))
+	w := p.NewTableWriter(table)
+	for i := 0; i < 500; i++ {
+		style := NewCellStyle(nil, nil, nil, AlignLeft, AlignTop)
+		if err := w.WriteRow(NewCell("row", style, 0, 0)); err != nil {
+			t.Fatalf("WriteRow: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if p.Engine.PageNo() < 5 {
+		t.Fatalf("500 rows fit on %d pages", p.Engine.PageNo())
+	}
+	if len(w.styles) > 100 {
+		t.Errorf("%d styles kept for 500 rows", len(w.styles))
+	}
+}
@@ -0,0 +1,324 @@
+package gopdf
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+func NewTotal(column int, aggregate string, format string) *Total {
+	t := &Total{
+		Column: column,
+		Format: format,
+	}
+	t.SetAggregate(aggregate)
+	return t
+}
+
+/*
+Total aggregates the numbers of a column of a table, counted from 0.
+Format formats the result with fmt.Sprintf, such as "%.2f", when its verb
+suits the result; by default, results have as many decimals as the numbers
+aggregated.
+*/
+type Total struct {
+	Column    int    `json:"column"`
+	Aggregate string `json:"aggregate"`
+	Format    string `json:"format"`
+}
+
+/*
+SetAggregate sets the aggregate function of the total, one of AggregateSum,
+AggregateCount, AggregateAverage, AggregateMin or AggregateMax.
+Counts count the cells that are not empty; the others only use numbers.
+By default, the aggregate is the sum.
+*/
+func (t *Total) SetAggregate(aggregate string) {
+	switch aggregate {
+	case AggregateSum, AggregateCount, AggregateAverage, AggregateMin, AggregateMax:
+		t.Aggregate = aggregate
+	default:
+		t.Aggregate = AggregateSum
+	}
+}
+
+func NewTableTotals(label string, totals ...*Total) *TableTotals {
+	return &TableTotals{
+		Label:  label,
+		Totals: totals,
+	}
+}
+
+/*
+TableTotals adds rows of totals to the end of a table, before its footer
+rows. The label of each row is written in LabelColumn.
+
+When the table breaks across pages, a page of the table may end with a row
+of the subtotals of the page and a row of the totals carried forward, and
+the next page start with a row of the totals brought forward. Each of these
+rows is written only if it has a label.
+*/
+type TableTotals struct {
+	Totals        []*Total   `json:"totals"`
+	Label         string     `json:"label"`
+	SubtotalLabel string     `json:"subtotal_label"`
+	CarriedLabel  string     `json:"carried_label"`
+	BroughtLabel  string     `json:"brought_label"`
+	LabelColumn   int        `json:"label_column"`
+	Style         *CellStyle `json:"style"`
+}
+
+/*
+SetPageLabels sets the labels of the rows of page subtotals, totals carried
+forward at the end of a page and totals brought forward at the start of the
+next, such as "Page total", "Carried forward" and "Brought forward".
+*/
+func (t *TableTotals) SetPageLabels(subtotal, carried, brought string) {
+	t.SubtotalLabel = subtotal
+	t.CarriedLabel = carried
+	t.BroughtLabel = brought
+}
+
+func (t *TableTotals) SetLabelColumn(column int) {
+	t.LabelColumn = column
+}
+
+/*
+SetStyle sets the cell style of the rows of totals. The footer layer of the
+table style applies on top of it.
+*/
+func (t *TableTotals) SetStyle(style *CellStyle) {
+	t.Style = style
+}
+
+/*
+aggregator accumulates the cells of a column.
+*/
+type aggregator struct {
+	count    int
+	numbers  int
+	sum      float64
+	min      float64
+	max      float64
+	decimals int
+}
+
+func (a *aggregator) add(text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	a.count++
+	v, ok := cellNumber(text)
+	if !ok {
+		return
+	}
+	if a.numbers == 0 || v < a.min {
+		a.min = v
+	}
+	if a.numbers == 0 || v > a.max {
+		a.max = v
+	}
+	a.numbers++
+	a.sum += v
+	if i := strings.IndexByte(text, '.'); i >= 0 {
+		decimals := strings.IndexFunc(text[i+1:], func(r rune) bool { return !unicode.IsDigit(r) })
+		if decimals < 0 {
+			decimals = len(text) - i - 1
+		}
+		a.decimals = max(a.decimals, decimals)
+	}
+}
+
+/*
+format returns the aggregate of the total as text, or "" if there is no
+number to aggregate.
+*/
+func (a *aggregator) format(total *Total) string {
+	if total.Aggregate == AggregateCount {
+		// Counts take the float verbs of formats shared with other totals.
+		if arg, ok := formatArg(formatVerb(total.Format), a.count); ok {
+			return fmt.Sprintf(total.Format, arg)
+		}
+		return strconv.Itoa(a.count)
+	}
+	if a.numbers == 0 && total.Aggregate != AggregateSum {
+		return ""
+	}
+	v, decimals := a.sum, a.decimals
+	switch total.Aggregate {
+	case AggregateAverage:
+		v, decimals = a.sum/float64(a.numbers), max(decimals, 2)
+	case AggregateMin:
+		v = a.min
+	case AggregateMax:
+		v = a.max
+	}
+	if arg, ok := formatArg(formatVerb(total.Format), v); ok {
+		return fmt.Sprintf(total.Format, arg)
+	}
+	return strconv.FormatFloat(v, 'f', decimals, 64)
+}
+
+/*
+addTotals adds the cells of a block of body rows to the page and running
+totals.
+*/
+func (w *TableWriter) addTotals(block *tableBlock) {
+	totals := w.table.Totals
+	if totals == nil {
+		return
+	}
+	if w.pageTotals == nil {
+		w.pageTotals = make([]aggregator, len(totals.Totals))
+		w.runningTotals = make([]aggregator, len(totals.Totals))
+	}
+	for i, total := range totals.Totals {
+		for _, c := range block.cells {
+			if c.Col == total.Column {
+				w.pageTotals[i].add(c.Cell.Text)
+				w.runningTotals[i].add(c.Cell.Text)
+			}
+		}
+	}
+}
+
+/*
+totalsRow returns a row of cells with a label and the given totals.
+*/
+func (w *TableWriter) totalsRow(label string, aggregators []aggregator) []*Cell {
+	totals := w.table.Totals
+	if aggregators == nil {
+		aggregators = make([]aggregator, len(totals.Totals))
+	}
+	if w.totalStyles == nil {
+		base := totals.Style
+		if base == nil {
+			base = NewCellStyle(nil, nil, nil, "", "")
+		}
+		w.totalStyles = make([]*CellStyle, len(w.columns))
+		for i := range w.totalStyles {
+			w.totalStyles[i] = base
+			if i < len(w.table.Columns) {
+				w.totalStyles[i] = alignedStyle(base, w.table.Columns[i].HAlign)
+			}
+		}
+	}
+	row := make([]*Cell, len(w.columns))
+	for i := range row {
+		row[i] = NewCell("", w.totalStyles[i], 0, 0)
+	}
+	if totals.LabelColumn >= 0 && totals.LabelColumn < len(row) {
+		row[totals.LabelColumn].Text = label
+	}
+	for i, total := range totals.Totals {
+		if total.Column >= 0 && total.Column < len(row) {
+			row[total.Column].Text = aggregators[i].format(total)
+		}
+	}
+	return row
+}
+
+/*
+totalsBlock returns a measured block of footer rows.
+*/
+func (w *TableWriter) totalsBlock(rows [][]*Cell) *tableBlock {
+	block, _ := placeCells(rows, len(w.columns))
+	w.table.Style.styleBlock(block, tableFooter, 0, w.styles)
+	w.pdf.measureBlock(block, w.columns)
+	return block
+}
+
+/*
+closingBlock returns the rows ending a page the table continues after,
+or nil if there are none.
+*/
+func (w *TableWriter) closingBlock() *tableBlock {
+	totals := w.table.Totals
+	if totals == nil {
+		return nil
+	}
+	var rows [][]*Cell
+	if totals.SubtotalLabel != "" {
+		rows = append(rows, w.totalsRow(totals.SubtotalLabel, w.pageTotals))
+	}
+	if totals.CarriedLabel != "" {
+		rows = append(rows, w.totalsRow(totals.CarriedLabel, w.runningTotals))
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return w.totalsBlock(rows)
+}
+
+/*
+closingHeight returns the height of the closing rows of the page if a block
+of body rows is added to it, measured with the totals including the block,
+as the totals may wrap to more lines as they grow.
+*/
+func (w *TableWriter) closingHeight(block *tableBlock) float64 {
+	if w.table.Totals == nil {
+		return 0
+	}
+	pageTotals, runningTotals := slices.Clone(w.pageTotals), slices.Clone(w.runningTotals)
+	w.addTotals(block)
+	closing := w.closingBlock()
+	w.pageTotals, w.runningTotals = pageTotals, runningTotals
+	if closing == nil {
+		return 0
+	}
+	return closing.height()
+}
+
+/*
+openingBlock returns the row of totals brought forward to a page the table
+continues on, or nil if there is none.
+*/
+func (w *TableWriter) openingBlock() *tableBlock {
+	totals := w.table.Totals
+	if totals == nil || totals.BroughtLabel == "" || w.pages == 0 {
+		return nil
+	}
+	return w.totalsBlock([][]*Cell{w.totalsRow(totals.BroughtLabel, w.runningTotals)})
+}
+
+/*
+endBlock returns the rows ending the table: the subtotals of the last page
+if the table broke across pages and the page has body rows, the totals, and
+the footer rows.
+*/
+func (w *TableWriter) endBlock() *tableBlock {
+	var rows [][]*Cell
+	if totals := w.table.Totals; totals != nil {
+		if totals.SubtotalLabel != "" && w.pages > 0 && w.rowsOnPage > 0 {
+			rows = append(rows, w.totalsRow(totals.SubtotalLabel, w.pageTotals))
+		}
+		rows = append(rows, w.totalsRow(totals.Label, w.runningTotals))
+	}
+	rows = append(rows, w.table.Footer...)
+	if len(rows) == 0 {
+		return nil
+	}
+	return w.totalsBlock(rows)
+}
+
+/*
+breakPage ends the page with the closing rows and moves to the next.
+*/
+func (w *TableWriter) breakPage() {
+	p := w.pdf
+	if block := w.closingBlock(); block != nil {
+		p.drawBlock(block, w.x, w.columns)
+	}
+	p.continuePage()
+	w.rowsOnPage = 0
+	w.pages++
+	// Styles are shared by the cells of a page, so that streamed rows with
+	// styles of their own are not kept for the whole table.
+	clear(w.styles)
+	for i := range w.pageTotals {
+		w.pageTotals[i] = aggregator{}
+	}
+}
@@ -0,0 +1,164 @@
+package gopdf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestAggregator(t *testing.T) {
+	var a aggregator
+	for _, text := range []string{"$1,200.00", " 300 ", "", "n/a", "-50.5", "€ 1 000"} {
+		a.add(text)
+	}
+	tests := []struct {
+		aggregate, format, want string
+	}{
+		{AggregateSum, "", "2449.50"},
+		{AggregateSum, "$%.1f", "$2449.5"},
+		{AggregateCount, "", "5"},
+		{AggregateCount, "%.2f", "5.00"},
+		{AggregateCount, "%d rows", "5 rows"},
+		{AggregateAverage, "", "612.38"},
+		{AggregateMin, "", "-50.50"},
+		{AggregateMax, "%.0f", "1200"},
+		{AggregateSum, "%d", "2449.50"},
+	}
+	for _, tt := range tests {
+		if got := a.format(NewTotal(0, tt.aggregate, tt.format)); got != tt.want {
+			t.Errorf("%s with %q = %q, want %q", tt.aggregate, tt.format, got, tt.want)
+		}
+	}
+
+	var empty aggregator
+	empty.add("none")
+	if got := empty.format(NewTotal(0, AggregateMax, "")); got != "" {
+		t.Errorf("max of no numbers = %q, want empty", got)
+	}
+	if got := empty.format(NewTotal(0, AggregateSum, "")); got != "0" {
+		t.Errorf("sum of no numbers = %q, want 0", got)
+	}
+}
+
+func TestCellNumber(t *testing.T) {
+	tests := map[string]float64{
+		"12":        12,
+		"1,234.5":   1234.5,
+		"$1,200.00": 1200,
+		"-$3":       -3,
+		"£ 7":       7,
+		"15%":       15,
+		"1 000 000": 1000000,
+	}
+	for text, want := range tests {
+		if got, ok := cellNumber(text); !ok || got != want {
+			t.Errorf("cellNumber(%q) = %v, %v, want %v", text, got, ok, want)
+		}
+	}
+	for _, text := range []string{"", "$", "abc", "12 apples", "NaN", "Inf", "-infinity", "1e400"} {
+		if _, ok := cellNumber(text); ok {
+			t.Errorf("cellNumber(%q) is a number", text)
+		}
+	}
+}
+
+func TestTableTotalsAcrossPages(t *testing.T) {
+	p := newTestPDF()
+	table := NewTable(nil)
+	table.AddHeaderRow(NewCell("Item", nil, 0, 0), NewCell("Amount", nil, 0, 0))
+	for i := 1; i <= 150; i++ {
+		table.AddRow(NewCell(fmt.Sprintf("item %d", i), nil, 0, 0), NewCell(fmt.Sprintf("$%d", i), nil, 0, 0))
+	}
+	totals := NewTableTotals("Total", NewTotal(1, AggregateSum, "%.0f"))
+	totals.SetPageLabels("Page total", "Carried forward", "Brought forward")
+	table.SetTotals(totals)
+	if err := p.WriteTableRows(table); err != nil {
+		t.Fatalf("WriteTableRows: %v", err)
+	}
+	pages := pageOutput(t, p)
+	if len(pages) < 2 {
+		t.Fatalf("the table fits on %d page", len(pages))
+	}
+	var carried int
+	for i, page := range pages {
+		drawn := texts(page)
+		values := map[string]int{}
+		var pageSum int
+		for j, d := range drawn {
+			if j+1 < len(drawn) {
+				if n, err := strconv.Atoi(drawn[j+1].Text); err == nil {
+					values[d.Text] = n
+				}
+			}
+			if n, ok := cellNumber(d.Text); ok && len(d.Text) > 1 && d.Text[0] == '$' {
+				pageSum += int(n)
+			}
+		}
+		if i > 0 && values["Brought forward"] != carried {
+			t.Errorf("page %d brings forward %d, want %d", i+1, values["Brought forward"], carried)
+		}
+		if values["Page total"] != pageSum {
+			t.Errorf("page %d total = %d, want %d", i+1, values["Page total"], pageSum)
+		}
+		if last := i == len(pages)-1; !last {
+			carried += pageSum
+			if values["Carried forward"] != carried {
+				t.Errorf("page %d carries forward %d, want %d", i+1, values["Carried forward"], carried)
+			}
+			if drawn[len(drawn)-1].Y < p.PageMarginBottom*p.Engine.GetConversionRatio() {
+				t.Errorf("page %d: closing rows drawn in the bottom margin", i+1)
+			}
+		} else if values["Total"] != 150*151/2 {
+			t.Errorf("total = %d, want %d", values["Total"], 150*151/2)
+		}
+	}
+}
+
+func TestTableTotalsGrowingPastColumnWidth(t *testing.T) {
+	p := newTestPDF()
+	table := NewTable(nil)
+	table.Columns = []*Column{NewColumn("Item", 100, 0), NewColumn("Amount", 24, 0)}
+	for i := 1; i <= 300; i++ {
+		table.AddRow(NewCell(fmt.Sprintf("item %d", i), nil, 0, 0), NewCell("99999", nil, 0, 0))
+	}
+	totals := NewTableTotals("Total", NewTotal(1, AggregateSum, ""))
+	totals.SetPageLabels("Page total", "Carried forward", "Brought forward")
+	table.SetTotals(totals)
+	if err := p.WriteTableRows(table); err != nil {
+		t.Fatalf("WriteTableRows: %v", err)
+	}
+	for i, page := range pageOutput(t, p) {
+		for _, d := range texts(page) {
+			if d.Y < p.PageMarginBottom*p.Engine.GetConversionRatio() {
+				t.Errorf("page %d: %q drawn in the bottom margin", i+1, d.Text)
+			}
+		}
+	}
+}
+
+func TestTableTotalsEndOnNewPage(t *testing.T) {
+	p := newTestPDF()
+	table := NewTable(nil)
+	for i := 1; i <= 50; i++ {
+		table.AddRow(NewCell(fmt.Sprintf("item %d", i), nil, 0, 0), NewCell("1", nil, 0, 0))
+	}
+	table.AddFooterRow(NewCell("Notes"+strings.Repeat("\nnote", 30), nil, 0, 0), NewCell("", nil, 0, 0))
+	totals := NewTableTotals("Total", NewTotal(1, AggregateSum, ""))
+	totals.SetPageLabels("Page total", "Carried forward", "Brought forward")
+	table.SetTotals(totals)
+	if err := p.WriteTableRows(table); err != nil {
+		t.Fatalf("WriteTableRows: %v", err)
+	}
+	pages := pageOutput(t, p)
+	if len(pages) != 2 {
+		t.Fatalf("the table is on %d pages, want the end rows moved to page 2", len(pages))
+	}
+	for _, d := range texts(pages[1]) {
+		if d.Text == "Page total" {
+			t.Errorf("a page total is written on a page without body rows")
+		}
+	}
+	findText(t, pages[1], "Brought forward")
+	findText(t, pages[1], "Total")
+}
@@ -0,0 +1,293 @@
+package gopdf
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func testTable(rows int) *Table {
+	table := NewTable(nil)
+	table.AddHeaderRow(NewCell("Name", nil, 0, 0), NewCell("Amount", nil, 0, 0))
+	for i := 1; i <= rows; i++ {
+		table.AddRow(NewCell(fmt.Sprintf("row %d", i), nil, 0, 0), NewCell(fmt.Sprint(i), nil, 0, 0))
+	}
+	table.AddFooterRow(NewCell("End", nil, 0, 0), NewCell("", nil, 0, 0))
+	return table
+}
+
+func TestTablePagination(t *testing.T) {
+	p := newTestPDF()
+	if err := p.WriteTableRows(testTable(150)); err != nil {
+		t.Fatalf("WriteTableRows: %v", err)
+	}
+	pages := pageOutput(t, p)
+	if len(pages) < 2 {
+		t.Fatalf("150 rows fit on %d page", len(pages))
+	}
+	var rows []string
+	for i, page := range pages {
+		drawn := texts(page)
+		if len(drawn) == 0 || drawn[0].Text != "Name" {
+			t.Errorf("page %d does not start with the header row", i+1)
+			continue
+		}
+		for _, d := range drawn {
+			if d.Y < p.PageMarginBottom*p.Engine.GetConversionRatio() {
+				t.Errorf("page %d: %q drawn in the bottom margin", i+1, d.Text)
+			}
+			if len(d.Text) > 4 && d.Text[:4] == "row " {
+				rows = append(rows, d.Text)
+			}
+		}
+		if hasEnd := slices.ContainsFunc(drawn, func(d drawnText) bool { return d.Text == "End" }); hasEnd != (i == len(pages)-1) {
+			t.Errorf("page %d: footer drawn = %v", i+1, hasEnd)
+		}
+	}
+	if len(rows) != 150 || rows[0] != "row 1" || rows[149] != "row 150" {
+		t.Errorf("drew %d rows, from %q", len(rows), rows[0])
+	}
+}
+
+func TestTableWriterMatchesWriteTableRows(t *testing.T) {
+	table := testTable(80)
+	want := newTestPDF()
+	want.WriteTableRows(table)
+
+	got := newTestPDF()
+	w := got.NewTableWriter(&Table{Header: table.Header, Footer: table.Footer})
+	for _, row := range table.Rows {
+		if err := w.WriteRow(row...); err != nil {
+			t.Fatalf("WriteRow: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if !slices.Equal(pageOutput(t, got), pageOutput(t, want)) {
+		t.Errorf("streamed rows are drawn differently")
+	}
+}
+
+func TestEmptyTableDrawsHeader(t *testing.T) {
+	p := newTestPDF()
+	table := testTable(0)
+	table.Footer = nil
+	if err := p.WriteTableRows(table); err != nil {
+		t.Fatalf("WriteTableRows: %v", err)
+	}
+	drawn := texts(pageOutput(t, p)[0])
+	if len(drawn) != 2 || drawn[0].Text != "Name" || drawn[1].Text != "Amount" {
+		t.Errorf("drawn = %+v, want the header row", drawn)
+	}
+}
+
+func TestRowTallerThanPage(t *testing.T) {
+	p := newTestPDF()
+	tall := strings.Repeat("line\n", 100)
+	table := NewTable(nil).AddRow(NewCell("short", nil, 0, 0)).AddRow(NewCell(tall, nil, 0, 0))
+	if err := p.WriteTableRows(table); err != nil {
+		t.Fatalf("WriteTableRows: %v", err)
+	}
+	pages := pageOutput(t, p)
+	if len(pages) < 3 {
+		t.Fatalf("table drawn on %d pages, want the tall row split from page 2", len(pages))
+	}
+	var lines int
+	for i, page := range pages {
+		for _, d := range texts(page) {
+			if d.Text != "line" {
+				continue
+			}
+			if i == 0 {
+				t.Fatal("the tall row starts on the page of the short row")
+			}
+			lines++
+			if y := p.PageHeight - d.Y; y < p.PageMarginTop || y > p.pageBreakTrigger() {
+				t.Errorf("line drawn at %.2f on page %d, outside of the page body", y, i+1)
+			}
+		}
+	}
+	if lines != 100 {
+		t.Errorf("%d lines of the tall row drawn, want each of the 100 once", lines)
+	}
+}
+
+func TestWriteTableTallerThanPage(t *testing.T) {
+	p := newTestPDF()
+	if err := p.WriteTable([]*Cell{NewCell(strings.Repeat("line\n", 120), nil, 0, 0)}, nil); err != nil {
+		t.Fatalf("WriteTable: %v", err)
+	}
+	p.LineBreak(nil)
+	if err := p.WriteText("after", nil); err != nil {
+		t.Fatalf("WriteText after the tall row: %v", err)
+	}
+	pages := pageOutput(t, p)
+	findText(t, pages[len(pages)-1], "after")
+}
+
+func TestWriteTableMovesRowToNextPage(t *testing.T) {
+	p := newTestPDF()
+	p.Engine.SetY(p.pageBreakTrigger() - 5)
+	if err := p.WriteTable([]*Cell{NewCell("first\nsecond", nil, 0, 0)}, nil); err != nil {
+		t.Fatalf("WriteTable: %v", err)
+	}
+	pages := pageOutput(t, p)
+	if len(pages) != 2 {
+		t.Fatalf("row drawn on %d pages, want 2", len(pages))
+	}
+	findText(t, pages[1], "first")
+	findText(t, pages[1], "second")
+}
+
+func TestPlaceCellsWithSpans(t *testing.T) {
+	cell := func(text string, colSpan, rowSpan int) *Cell {
+		c := NewCell(text, nil, 0, 0)
+		c.SetColSpan(colSpan)
+		c.SetRowSpan(rowSpan)
+		return c
+	}
+	rows := [][]*Cell{
+		{cell("a", 1, 2), cell("b", 2, 1)},
+		{cell("c", 1, 1), cell("d", 1, 1)},
+		{cell("e", 3, 1)},
+	}
+	block, pending := placeCells(rows, 3)
+	if pending {
+		t.Errorf("placeCells reports a pending row span")
+	}
+	want := []blockCell{
+		{Row: 0, Col: 0, ColSpan: 1, RowSpan: 2},
+		{Row: 0, Col: 1, ColSpan: 2, RowSpan: 1},
+		{Row: 1, Col: 1, ColSpan: 1, RowSpan: 1},
+		{Row: 1, Col: 2, ColSpan: 1, RowSpan: 1},
+		{Row: 2, Col: 0, ColSpan: 3, RowSpan: 1},
+	}
+	if len(block.cells) != len(want) {
+		t.Fatalf("placed %d cells, want %d", len(block.cells), len(want))
+	}
+	for i, c := range block.cells {
+		c.Cell = nil
+		if c != want[i] {
+			t.Errorf("cell %d placed at %+v, want %+v", i, c, want[i])
+		}
+	}
+
+	if _, pending := placeCells(rows[:1], 3); !pending {
+		t.Errorf("placeCells does not report the row span below the last row")
+	}
+	if n := blockRows(rows, 3); n != 2 {
+		t.Errorf("blockRows = %d, want the 2 rows joined by the span", n)
+	}
+}
+
+func TestRowSpanIsKeptOnOnePage(t *testing.T) {
+	p := newTestPDF()
+	table := NewTable(nil)
+	for i := 0; i < 200; i++ {
+		first := NewCell(fmt.Sprintf("span %d", i), nil, 0, 0)
+		first.SetRowSpan(3)
+		table.AddRow(first, NewCell(fmt.Sprintf("a %d", i), nil, 0, 0))
+		table.AddRow(NewCell(fmt.Sprintf("b %d", i), nil, 0, 0))
+		table.AddRow(NewCell(fmt.Sprintf("c %d", i), nil, 0, 0))
+	}
+	if err := p.WriteTableRows(table); err != nil {
+		t.Fatalf("WriteTableRows: %v", err)
+	}
+	pages := pageOutput(t, p)
+	if len(pages) < 2 {
+		t.Fatalf("the table fits on %d page", len(pages))
+	}
+	for i, page := range pages {
+		drawn := texts(page)
+		// Every page starts with a spanning cell and ends with the last row
+		// it spans.
+		if first := drawn[0].Text; len(first) < 5 || first[:5] != "span " {
+			t.Errorf("page %d starts with %q", i+1, first)
+		}
+		if last := drawn[len(drawn)-1].Text; last[:2] != "c " {
+			t.Errorf("page %d ends with %q", i+1, last)
+		}
+	}
+}
+
+func TestCellVerticalAlignment(t *testing.T) {
+	for _, tt := range []struct {
+		align string
+		line  string // the line of the tall cell the short cell is level with
+	}{
+		{AlignTop, "1"},
+		{AlignMiddle, "2"},
+		{AlignBottom, "3"},
+	} {
+		p := newTestPDF()
+		short := NewCell("short", NewCellStyle(nil, nil, &RGB{R: 200, G: 200, B: 200}, "", tt.align), 0, 0)
+		p.WriteTableRows(NewTable(nil).AddRow(NewCell("1\n2\n3", nil, 0, 0), short))
+		content := pageOutput(t, p)[0]
+		if got, want := findText(t, content, "short").Y, findText(t, content, tt.line).Y; !near(got, want) {
+			t.Errorf("%s: short cell at %.2f, want %.2f", tt.align, got, want)
+		}
+		// The fill covers the full row height of three 12pt lines.
+		if !strings.Contains(content, "-36.00 re f") {
+			t.Errorf("%s: the fill does not cover the row:\n%s", tt.align, content)
+		}
+	}
+}
+
+func TestCellBaselineAlignment(t *testing.T) {
+	p := newTestPDF()
+	style := func(size float64) *CellStyle {
+		return NewCellStyle(NewFontStyle("", size, 0, nil, false, false, false), nil, nil, "", AlignBaseline)
+	}
+	p.WriteTableRows(NewTable(nil).AddRow(
+		NewCell("small", style(8), 0, 0),
+		NewCell("large", style(24), 0, 0),
+		NewCell("top", NewCellStyle(NewFontStyle("", 8, 0, nil, false, false, false), nil, nil, "", AlignTop), 0, 0),
+	))
+	content := pageOutput(t, p)[0]
+	small, large, top := findText(t, content, "small"), findText(t, content, "large"), findText(t, content, "top")
+	if !near(small.Y, large.Y) {
+		t.Errorf("baselines at %.2f and %.2f, want them level", small.Y, large.Y)
+	}
+	if top.Y <= small.Y {
+		t.Errorf("top aligned text at %.2f is not above the baseline %.2f", top.Y, small.Y)
+	}
+}
+
+func TestCellPadding(t *testing.T) {
+	p := newTestPDF()
+	style := NewCellStyle(nil, nil, nil, "", "")
+	style.SetPadding(NewPadding(2, 4, 0, 2))
+	padded := NewCell("padded", style, 0, 0)
+	override := NewCell("override", style, 0, 0)
+	override.SetPadding(NewPadding(6, 8, 0, 6))
+	table := NewTable(nil).
+		AddRow(NewCell("plain", nil, 0, 0), NewCell("", nil, 0, 0), NewCell("", nil, 0, 0)).
+		AddRow(padded, override, NewCell("next", nil, 0, 0))
+	p.WriteTableRows(table)
+	content := pageOutput(t, p)[0]
+	k := p.Engine.GetConversionRatio()
+	cm := p.Engine.GetCellMargin()
+	column := p.PageBodyWidth / 3
+	plain, next := findText(t, content, "plain"), findText(t, content, "next")
+
+	if got, want := findText(t, content, "padded").X, plain.X+(4-cm)*k; !near(got, want) {
+		t.Errorf("padded text at x %.2f, want %.2f", got, want)
+	}
+	if got, want := findText(t, content, "override").X, (p.PageMarginLeft+column+8)*k; !near(got, want) {
+		t.Errorf("cell padding text at x %.2f, want %.2f", got, want)
+	}
+	// The row holds the largest padding, and unpadded cells are drawn at
+	// its top.
+	lineHeight := 12.0
+	if got, want := plain.Y-next.Y, lineHeight; !near(got, want) {
+		t.Errorf("second row starts %.2f below the first, want %.2f", got, want)
+	}
+	if got, want := next.Y-findText(t, content, "override").Y, 6*k; !near(got, want) {
+		t.Errorf("padded text is %.2f below the row top, want %.2f", got, want)
+	}
+	if got, want := p.Engine.GetY(), p.PageMarginTop+lineHeight/k+(6+lineHeight/k+6); !near(got, want) {
+		t.Errorf("table ends at %.2f, want %.2f", got, want)
+	}
+}
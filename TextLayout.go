@@ -0,0 +1,251 @@
+package gopdf
+
+import (
+	"strings"
+	"unicode"
+)
+
+// obliqueAngle is the slant of synthetic italics, in degrees.
+const obliqueAngle = 12
+
+/*
+wrapText breaks text into lines that fit the given widths, as measured by
+measure. The first line fits firstWidth, which is smaller than
+width when text continues a line; if nothing fits there, the first line is
+empty. Newlines always break, and words wider than a line are broken
+between characters.
+*/
+func wrapText(measure func(string) float64, text string, firstWidth, width float64) []string {
+	var lines []string
+	limit := firstWidth
+	for _, para := range strings.Split(text, "\n") {
+		var line string
+		var lineWidth float64
+		for _, token := range splitWords(para) {
+			tokenWidth := measure(token)
+			if lineWidth+tokenWidth <= limit {
+				line += token
+				lineWidth += tokenWidth
+				continue
+			}
+			if line != "" || (len(lines) == 0 && limit < width) {
+				lines = append(lines, line)
+				limit = width
+				token = strings.TrimLeft(token, " ")
+				line, lineWidth = "", 0
+				if tokenWidth = measure(token); tokenWidth <= limit {
+					line, lineWidth = token, tokenWidth
+					continue
+				}
+			}
+			// The word alone is wider than a line.
+			for {
+				head, rest := fitRunes(measure, token, limit)
+				if rest == "" {
+					line, lineWidth = head, measure(head)
+					break
+				}
+				lines = append(lines, head)
+				limit = width
+				token = rest
+			}
+		}
+		lines = append(lines, line)
+		limit = width
+	}
+	return lines
+}
+
+/*
+wrapParagraphs wraps each paragraph of text to width like wrapText, and
+reports which lines end a paragraph, which justified text leaves unstretched.
+*/
+func wrapParagraphs(measure func(string) float64, text string, width float64) ([]string, []bool) {
+	var lines []string
+	var ends []bool
+	for _, para := range strings.Split(text, "\n") {
+		wrapped := wrapText(measure, para, width, width)
+		lines = append(lines, wrapped...)
+		for i := range wrapped {
+			ends = append(ends, i == len(wrapped)-1)
+		}
+	}
+	return lines, ends
+}
+
+/*
+lineAlign returns the alignment of a line, where justified lines that end a
+paragraph are aligned left.
+*/
+func lineAlign(align string, end bool) string {
+	if align == AlignJustify && end {
+		return AlignLeft
+	}
+	return align
+}
+
+/*
+fitRunes splits s into the longest prefix no wider than width and the rest.
+The prefix holds at least one rune, so that wrapping always makes progress.
+*/
+func fitRunes(measure func(string) float64, s string, width float64) (string, string) {
+	if measure(s) <= width {
+		return s, ""
+	}
+	var w float64
+	for i, r := range s {
+		w += measure(string(r))
+		if w > width && i > 0 {
+			return s[:i], s[i:]
+		}
+	}
+	return s, ""
+}
+
+/*
+splitWords splits a line into the units that wrapping keeps together:
+words with their leading spaces, and single CJK characters, which may be
+broken between.
+*/
+func splitWords(s string) []string {
+	var tokens []string
+	start := 0
+	inWord := false
+	for i, r := range s {
+		switch {
+		case isWideBreakable(r):
+			if i > start {
+				tokens = append(tokens, s[start:i])
+			}
+			tokens = append(tokens, string(r))
+			start = i + len(string(r))
+			inWord = false
+		case r == ' ':
+			if inWord {
+				tokens = append(tokens, s[start:i])
+				start = i
+				inWord = false
+			}
+		default:
+			inWord = true
+		}
+	}
+	if start < len(s) {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+func isWideBreakable(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+/*
+writeFlow writes flowing text like the engine's Write, for styles that need
+line layout.
+*/
+func (p *PDF) writeFlow(style *FontStyle, text string) {
+	cm := p.Engine.GetCellMargin()
+	right := p.PageWidth - p.PageMarginRight
+	measure := p.textMeasure(style)
+	lines := wrapText(measure, text, right-p.Engine.GetX()-2*cm, p.PageBodyWidth-2*cm)
+	for i, line := range lines {
+		if i > 0 {
+			p.Engine.Ln(p.lineHeight(style))
+		}
+		p.drawLine(style, measure(line)+2*cm, p.lineHeight(style), line, AlignLeft)
+	}
+}
+
+/*
+writeBox writes an aligned line of text like the engine's WriteAligned,
+for styles that need line layout and justified text.
+*/
+func (p *PDF) writeBox(style *FontStyle, text string, align string) {
+	cm := p.Engine.GetCellMargin()
+	measure := p.textMeasure(style)
+	lines := wrapText(measure, text, p.PageBodyWidth-2*cm, p.PageBodyWidth-2*cm)
+	for i, line := range lines {
+		if i > 0 {
+			p.Engine.Ln(p.lineHeight(style))
+		}
+		// The engine centres lines a cell margin to the right: do the same,
+		// so that lines are placed alike whichever way they are written.
+		x := p.PageMarginLeft
+		if align == AlignCenter {
+			x += cm
+		}
+		p.Engine.SetX(x)
+		p.drawLine(style, p.PageBodyWidth, p.lineHeight(style), line, lineAlign(align, i == len(lines)-1))
+	}
+}
+
+/*
+drawLine draws a line of text in a cell of width w at the current position
+and moves to the right of the cell. Page placeholders are resolved, fallback
+runs are drawn with their own fonts, and synthetic italics are slanted.
+Justified text is stretched to the width of the cell at its spaces.
+*/
+func (p *PDF) drawLine(style *FontStyle, w, h float64, text string, align string) {
+	text = p.resolvePlaceholders(text)
+	runs := style.textRuns(text)
+	// The engine would align page counts by the width of their aliases.
+	aligned := p.processHAlign(align) != "L" && p.layoutText(text) != text
+	if len(runs) == 1 && !style.syntheticItalic() && align != AlignJustify && !aligned {
+		style.setup(p)
+		p.Engine.CellFormat(w, h, text, "", 0, p.processHAlign(align), false, 0, "")
+		return
+	}
+	// Break the page first, as the engine would do it inside the transform.
+	if auto, margin := p.Engine.GetAutoPageBreak(); auto && p.Engine.GetY()+h > p.PageHeight-margin {
+		p.continuePage()
+	}
+	x, y := p.Engine.GetXY()
+	widths := make([]float64, len(runs))
+	var total float64
+	for i, run := range runs {
+		run.Style.setup(p)
+		widths[i] = p.Engine.GetStringWidth(p.layoutText(run.Text))
+		total += widths[i]
+	}
+	cm := p.Engine.GetCellMargin()
+	runX := x + cm
+	switch align {
+	case AlignCenter:
+		runX = x + (w-total)/2
+	case AlignRight:
+		runX = x + w - cm - total
+	}
+	// Justified text gets the extra width at each of its spaces.
+	var extra float64
+	if spaces := strings.Count(text, " "); align == AlignJustify && spaces > 0 {
+		extra = max((w-2*cm-total)/float64(spaces), 0)
+	}
+	p.Engine.SetCellMargin(0)
+	for i, run := range runs {
+		run.Style.setup(p)
+		slanted := run.Style.syntheticItalic()
+		if slanted {
+			_, fontSize := p.Engine.GetFontSize()
+			p.Engine.TransformBegin()
+			p.Engine.TransformSkewX(obliqueAngle, runX, y+h/2+0.3*fontSize)
+		}
+		if extra > 0 {
+			for _, piece := range strings.SplitAfter(run.Text, " ") {
+				width := p.Engine.GetStringWidth(p.layoutText(piece))
+				p.Engine.SetXY(runX, y)
+				p.Engine.CellFormat(width, h, piece, "", 0, "L", false, 0, "")
+				runX += width + extra*float64(strings.Count(piece, " "))
+			}
+		} else {
+			p.Engine.SetXY(runX, y)
+			p.Engine.CellFormat(widths[i], h, run.Text, "", 0, "L", false, 0, "")
+			runX += widths[i]
+		}
+		if slanted {
+			p.Engine.TransformEnd()
+		}
+	}
+	p.Engine.SetCellMargin(cm)
+	p.Engine.SetXY(x+w, y)
+}
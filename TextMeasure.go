@@ -0,0 +1,54 @@
+package gopdf
+
+/*
+TextWidth returns the width of a line of text in a font style, in the unit
+of the document. A nil style is the default font style. Measuring keeps the
+font and text colour in use for the next text written.
+*/
+func (p *PDF) TextWidth(text string, style *FontStyle) float64 {
+	if style == nil {
+		style = p.DefaultFontStyle
+	}
+	restoreFont := p.keepFont()
+	defer restoreFont()
+	return p.textMeasure(style)(text)
+}
+
+/*
+WrapText breaks text into the lines it is written on within the given
+width, such as the inner width of a table cell. Newlines always break, and
+words wider than a line are broken between characters. WriteTextBox wraps
+text within the page body less twice the cell margin of the engine.
+*/
+func (p *PDF) WrapText(text string, style *FontStyle, width float64) []string {
+	if style == nil {
+		style = p.DefaultFontStyle
+	}
+	restoreFont := p.keepFont()
+	defer restoreFont()
+	lines, _ := wrapParagraphs(p.textMeasure(style), text, width)
+	return lines
+}
+
+/*
+TextHeight returns the height of text wrapped within the given width.
+*/
+func (p *PDF) TextHeight(text string, style *FontStyle, width float64) float64 {
+	if style == nil {
+		style = p.DefaultFontStyle
+	}
+	return float64(len(p.WrapText(text, style, width))) * p.lineHeight(style)
+}
+
+/*
+ParagraphHeight returns the height of a paragraph written within the given
+width, as by WriteParagraph across the page body.
+*/
+func (p *PDF) ParagraphHeight(paragraph *Paragraph, style *FontStyle, width float64) float64 {
+	if style == nil {
+		style = p.DefaultFontStyle
+	}
+	restoreFont := p.keepFont()
+	defer restoreFont()
+	return paragraphHeight(p.layoutParagraph(paragraph, style, width))
+}
@@ -0,0 +1,118 @@
+package gopdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTextWidth(t *testing.T) {
+	p := newTestPDF()
+	style := NewFontStyle(FontFamilyCourier, 10, 0, nil, false, false, false)
+	// Courier glyphs are 0.6 of the font size wide.
+	if got := p.TextWidth("hello", style); !near(got, 5*6) {
+		t.Errorf("TextWidth = %.2f, want 30", got)
+	}
+	if got, want := p.TextWidth("hello", nil), p.TextWidth("hello", p.DefaultFontStyle); got != want {
+		t.Errorf("TextWidth with a nil style = %.2f, want %.2f", got, want)
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	p := newTestPDF()
+	style := NewFontStyle(FontFamilyCourier, 10, 0, nil, false, false, false)
+	// 10 characters fit in 60 points.
+	lines := p.WrapText("aaa bbb ccc dddddddddddddd\neee", style, 60)
+	want := []string{"aaa bbb", "ccc", "dddddddddd", "dddd", "eee"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("WrapText = %q, want %q", lines, want)
+	}
+	if got := p.TextHeight("aaa bbb ccc", style, 60); !near(got, 20) {
+		t.Errorf("TextHeight = %.2f, want two lines of 10", got)
+	}
+}
+
+func TestMeasuringKeepsFont(t *testing.T) {
+	p := newTestPDF()
+	style := NewFontStyle(FontFamilyTimes, 20, 0, NewRGB(255, 0, 0), false, false, false)
+	other := NewFontStyle(FontFamilyCourier, 8, 0, NewRGB(0, 0, 255), true, false, false)
+	p.WriteText("before", style)
+	key := p.fontKey
+	size, _ := p.Engine.GetFontSize()
+	r, g, b := p.Engine.GetTextColor()
+
+	p.TextWidth("measured", other)
+	p.WrapText("measured text", other, 50)
+	p.TextHeight("measured text", other, 50)
+	p.ParagraphHeight(NewParagraph().AddText("measured", other), nil, 50)
+	if s, _ := p.Engine.GetFontSize(); p.fontKey != key || s != size {
+		t.Errorf("measuring changed the font to %s", p.fontKey)
+	}
+	if r2, g2, b2 := p.Engine.GetTextColor(); r2 != r || g2 != g || b2 != b {
+		t.Errorf("measuring changed the text colour to %d, %d, %d", r2, g2, b2)
+	}
+	// Links are written in the font in use.
+	p.WriteLink("link", "https://example.com", nil)
+	content := pageOutput(t, p)[0]
+	if got, want := lastFont(before(t, content, "link")), lastFont(before(t, content, "before")); got != want {
+		t.Errorf("link drawn in %s, want %s", got, want)
+	}
+}
+
+func TestMeasuringInTheFontInUseWritesNothing(t *testing.T) {
+	write := func(measure bool) string {
+		p := newTestPDF()
+		p.WriteText("a", nil)
+		if measure {
+			p.TextWidth("b", nil)
+		}
+		p.WriteText("c", nil)
+		return pageOutput(t, p)[0]
+	}
+	if got, want := write(true), write(false); got != want {
+		t.Errorf("measuring in the font in use changed the page:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestJustifiedAlignment(t *testing.T) {
+	style := NewCellStyle(nil, nil, nil, AlignJustify, "")
+	if got := style.HAlignToEngineString(); got != "J" {
+		t.Errorf("HAlignToEngineString = %q, want J", got)
+	}
+	if got := New().processHAlign(AlignJustify); got != "J" {
+		t.Errorf("processHAlign = %q, want J", got)
+	}
+
+	text := strings.Repeat("justified words of text ", 12)
+	lastX := func(write func(p *PDF, align string)) (left, justified float64) {
+		for _, align := range []string{AlignLeft, AlignJustify} {
+			p := newTestPDF()
+			write(p, align)
+			drawn := texts(pageOutput(t, p)[0])
+			// The last word of the first line, which is not the last line.
+			var x float64
+			for _, d := range drawn {
+				if near(d.Y, drawn[0].Y) {
+					x = d.X
+				}
+			}
+			if align == AlignLeft {
+				left = x
+			} else {
+				justified = x
+			}
+		}
+		return left, justified
+	}
+	left, justified := lastX(func(p *PDF, align string) {
+		p.WriteTextBox(text, align, nil)
+	})
+	if justified <= left {
+		t.Errorf("WriteTextBox: justified line ends at %.2f, left aligned at %.2f", justified, left)
+	}
+	left, justified = lastX(func(p *PDF, align string) {
+		p.WriteTableRows(NewTable(nil).AddRow(NewCell(text, NewCellStyle(nil, nil, nil, align, ""), 0, 0)))
+	})
+	if justified <= left {
+		t.Errorf("table cell: justified line ends at %.2f, left aligned at %.2f", justified, left)
+	}
+}
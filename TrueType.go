@@ -0,0 +1,209 @@
+package gopdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+)
+
+/*
+trueTypeInfo holds the runes a TrueType font maps to glyphs, which font
+fallback needs before the font is handed to the engine. It is parsed once per
+font and shared by every document that uses the font.
+*/
+type trueTypeInfo struct {
+	runes []runeRange // sorted, non-overlapping ranges of mapped runes
+}
+
+type runeRange struct {
+	Lo, Hi rune
+}
+
+/*
+HasRune reports whether the font maps r to a glyph.
+*/
+func (t *trueTypeInfo) HasRune(r rune) bool {
+	i := sort.Search(len(t.runes), func(i int) bool { return t.runes[i].Hi >= r })
+	return i < len(t.runes) && t.runes[i].Lo <= r
+}
+
+var errTrueTypeTruncated = errors.New("truncated font data")
+
+type trueTypeData []byte
+
+func (d trueTypeData) u16(off int) (uint16, error) {
+	if off < 0 || off+2 > len(d) {
+		return 0, errTrueTypeTruncated
+	}
+	return binary.BigEndian.Uint16(d[off:]), nil
+}
+
+func (d trueTypeData) u32(off int) (uint32, error) {
+	if off < 0 || off+4 > len(d) {
+		return 0, errTrueTypeTruncated
+	}
+	return binary.BigEndian.Uint32(d[off:]), nil
+}
+
+/*
+parseTrueType checks the head, hhea and cmap tables of a TrueType font and
+reads its cmap.
+Only fonts with TrueType outlines are supported, as that is what the engine
+can embed.
+*/
+func parseTrueType(b []byte) (*trueTypeInfo, error) {
+	d := trueTypeData(b)
+	version, err := d.u32(0)
+	if err != nil {
+		return nil, err
+	}
+	switch version {
+	case 0x00010000, 0x74727565: // TrueType, 'true'
+	case 0x4f54544f: // 'OTTO'
+		return nil, errors.New("OpenType fonts with CFF outlines are not supported")
+	case 0x74746366: // 'ttcf'
+		return nil, errors.New("font collections are not supported")
+	default:
+		return nil, errors.New("not a TrueType font")
+	}
+	numTables, err := d.u16(4)
+	if err != nil {
+		return nil, err
+	}
+	tables := make(map[string]int, numTables)
+	for i := 0; i < int(numTables); i++ {
+		rec := 12 + i*16
+		if rec+16 > len(d) {
+			return nil, errTrueTypeTruncated
+		}
+		offset, _ := d.u32(rec + 8)
+		tables[string(d[rec:rec+4])] = int(offset)
+	}
+	for _, tag := range []string{"head", "hhea", "cmap"} {
+		if _, ok := tables[tag]; !ok {
+			return nil, errors.New("missing " + tag + " table")
+		}
+	}
+
+	info := &trueTypeInfo{}
+	if info.runes, err = parseCmap(d, tables["cmap"]); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func parseCmap(d trueTypeData, cmap int) ([]runeRange, error) {
+	numTables, err := d.u16(cmap + 2)
+	if err != nil {
+		return nil, err
+	}
+	// Prefer the full Unicode subtable, then the BMP one.
+	format4, format12 := -1, -1
+	for i := 0; i < int(numTables); i++ {
+		rec := cmap + 4 + i*8
+		platform, err := d.u16(rec)
+		if err != nil {
+			return nil, err
+		}
+		encoding, _ := d.u16(rec + 2)
+		offset, _ := d.u32(rec + 4)
+		sub := cmap + int(offset)
+		format, err := d.u16(sub)
+		if err != nil {
+			return nil, err
+		}
+		unicode := platform == 0 || (platform == 3 && (encoding == 1 || encoding == 10))
+		switch {
+		case unicode && format == 12 && format12 < 0:
+			format12 = sub
+		case unicode && format == 4 && format4 < 0:
+			format4 = sub
+		}
+	}
+	switch {
+	case format12 >= 0:
+		return parseCmapFormat12(d, format12)
+	case format4 >= 0:
+		return parseCmapFormat4(d, format4)
+	}
+	return nil, errors.New("no Unicode cmap subtable")
+}
+
+func parseCmapFormat4(d trueTypeData, sub int) ([]runeRange, error) {
+	segCountX2, err := d.u16(sub + 6)
+	if err != nil {
+		return nil, err
+	}
+	segCount := int(segCountX2) / 2
+	endCodes := sub + 14
+	startCodes := endCodes + segCount*2 + 2
+	idDeltas := startCodes + segCount*2
+	idRangeOffsets := idDeltas + segCount*2
+	var ranges []runeRange
+	for i := 0; i < segCount; i++ {
+		end, err := d.u16(endCodes + i*2)
+		if err != nil {
+			return nil, err
+		}
+		start, _ := d.u16(startCodes + i*2)
+		delta, _ := d.u16(idDeltas + i*2)
+		rangeOffset, err := d.u16(idRangeOffsets + i*2)
+		if err != nil {
+			return nil, err
+		}
+		for c := int(start); c <= int(end) && c != 0xffff; c++ {
+			var glyph uint16
+			if rangeOffset == 0 {
+				glyph = uint16(c) + delta
+			} else {
+				g, err := d.u16(idRangeOffsets + i*2 + int(rangeOffset) + (c-int(start))*2)
+				if err != nil {
+					return nil, err
+				}
+				if g != 0 {
+					glyph = g + delta
+				}
+			}
+			if glyph != 0 {
+				ranges = appendRune(ranges, rune(c))
+			}
+		}
+	}
+	return ranges, nil
+}
+
+func parseCmapFormat12(d trueTypeData, sub int) ([]runeRange, error) {
+	numGroups, err := d.u32(sub + 12)
+	if err != nil {
+		return nil, err
+	}
+	var ranges []runeRange
+	for i := 0; i < int(numGroups); i++ {
+		group := sub + 16 + i*12
+		start, err := d.u32(group)
+		if err != nil {
+			return nil, err
+		}
+		end, _ := d.u32(group + 4)
+		glyph, err := d.u32(group + 8)
+		if err != nil {
+			return nil, err
+		}
+		if glyph == 0 {
+			start++ // the first code maps to .notdef
+		}
+		if start <= end {
+			ranges = append(ranges, runeRange{Lo: rune(start), Hi: rune(end)})
+		}
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Lo < ranges[j].Lo })
+	return ranges, nil
+}
+
+func appendRune(ranges []runeRange, r rune) []runeRange {
+	if n := len(ranges); n > 0 && ranges[n-1].Hi == r-1 {
+		ranges[n-1].Hi = r
+		return ranges
+	}
+	return append(ranges, runeRange{Lo: r, Hi: r})
+}
@@ -0,0 +1,137 @@
+package gopdf
+
+import (
+	"encoding/binary"
+	"os"
+	"testing"
+)
+
+func TestParseTrueTypeFile(t *testing.T) {
+	b, err := os.ReadFile("testdata/DejaVuSansCondensed.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := parseTrueType(b)
+	if err != nil {
+		t.Fatalf("parseTrueType: %v", err)
+	}
+	for r, want := range map[rune]bool{'A': true, 'é': true, 'Ж': true, '中': false, 0x10FFFD: false} {
+		if got := info.HasRune(r); got != want {
+			t.Errorf("HasRune(%q) = %v, want %v", r, got, want)
+		}
+	}
+}
+
+/*
+testFont returns a minimal TrueType font with head, hhea and the given cmap
+subtable, for the Windows Unicode BMP encoding.
+*/
+func testFont(subtable []byte) []byte {
+	be := binary.BigEndian
+	head := make([]byte, 54)
+	be.PutUint16(head[18:], 1000)
+	hhea := make([]byte, 36)
+	be.PutUint16(hhea[4:], 800)
+	be.PutUint16(hhea[6:], uint16(0x10000-200))
+	cmap := make([]byte, 12, 12+len(subtable))
+	be.PutUint16(cmap[2:], 1)
+	be.PutUint16(cmap[4:], 3)
+	be.PutUint16(cmap[6:], 10)
+	be.PutUint32(cmap[8:], 12)
+	cmap = append(cmap, subtable...)
+
+	tables := []struct {
+		tag  string
+		data []byte
+	}{{"cmap", cmap}, {"head", head}, {"hhea", hhea}}
+	font := make([]byte, 12+16*len(tables))
+	be.PutUint32(font, 0x00010000)
+	be.PutUint16(font[4:], uint16(len(tables)))
+	for i, table := range tables {
+		rec := font[12+16*i:]
+		copy(rec, table.tag)
+		be.PutUint32(rec[8:], uint32(len(font)))
+		be.PutUint32(rec[12:], uint32(len(table.data)))
+		font = append(font, table.data...)
+	}
+	return font
+}
+
+func TestParseCmapFormat4(t *testing.T) {
+	be := binary.BigEndian
+	// Segments 'A'-'C' by delta, 'x'-'y' by glyph array with 'y' unmapped,
+	// and the final 0xFFFF segment.
+	segments := []struct{ start, end, delta uint16 }{
+		{'A', 'C', 10},
+		{'x', 'y', 0},
+		{0xffff, 0xffff, 1},
+	}
+	n := len(segments)
+	sub := make([]byte, 16+8*n+4)
+	be.PutUint16(sub, 4)
+	be.PutUint16(sub[6:], uint16(2*n))
+	for i, s := range segments {
+		be.PutUint16(sub[14+2*i:], s.end)
+		be.PutUint16(sub[16+2*n+2*i:], s.start)
+		be.PutUint16(sub[16+4*n+2*i:], s.delta)
+	}
+	// The range offset of 'x'-'y' points past the remaining offsets to the
+	// glyph array.
+	rangeOffsets := 16 + 6*n
+	be.PutUint16(sub[rangeOffsets+2:], uint16(2*(n-1)))
+	glyphs := 16 + 8*n
+	be.PutUint16(sub[glyphs:], 7)
+	be.PutUint16(sub[glyphs+2:], 0)
+
+	info, err := parseTrueType(testFont(sub))
+	if err != nil {
+		t.Fatalf("parseTrueType: %v", err)
+	}
+	for r, want := range map[rune]bool{'A': true, 'C': true, 'D': false, 'x': true, 'y': false, 0xffff: false} {
+		if got := info.HasRune(r); got != want {
+			t.Errorf("HasRune(%q) = %v, want %v", r, got, want)
+		}
+	}
+}
+
+func TestParseCmapFormat12(t *testing.T) {
+	be := binary.BigEndian
+	groups := []struct{ start, end, glyph uint32 }{
+		{0x1F600, 0x1F602, 5},
+		{0, 3, 0},
+	}
+	sub := make([]byte, 16+12*len(groups))
+	be.PutUint16(sub, 12)
+	be.PutUint32(sub[12:], uint32(len(groups)))
+	for i, g := range groups {
+		be.PutUint32(sub[16+12*i:], g.start)
+		be.PutUint32(sub[20+12*i:], g.end)
+		be.PutUint32(sub[24+12*i:], g.glyph)
+	}
+	info, err := parseTrueType(testFont(sub))
+	if err != nil {
+		t.Fatalf("parseTrueType: %v", err)
+	}
+	for r, want := range map[rune]bool{0: false, 1: true, 3: true, 0x1F600: true, 0x1F602: true, 0x1F603: false} {
+		if got := info.HasRune(r); got != want {
+			t.Errorf("HasRune(%#x) = %v, want %v", r, got, want)
+		}
+	}
+}
+
+func TestParseTrueTypeErrors(t *testing.T) {
+	font := testFont(make([]byte, 16))
+	tests := map[string][]byte{
+		"empty":      nil,
+		"cff":        []byte("OTTO\x00\x00\x00\x00"),
+		"collection": []byte("ttcf\x00\x00\x00\x00"),
+		"unknown":    []byte("abcd\x00\x00\x00\x00"),
+		"truncated":  font[:40],
+		"no unicode": font,
+	}
+	for name, b := range tests {
+		if _, err := parseTrueType(b); err == nil {
+			t.Errorf("%s: parseTrueType succeeded", name)
+		}
+	}
+}
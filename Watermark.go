@@ -0,0 +1,169 @@
+package gopdf
+
+func NewTextWatermark(text string, style *FontStyle) *Watermark {
+	return &Watermark{
+		Text:    text,
+		Style:   style,
+		Angle:   45,
+		Opacity: 0.3,
+		HAlign:  AlignCenter,
+		VAlign:  AlignMiddle,
+	}
+}
+
+func NewImageWatermark(b []byte, width, height float64) *Watermark {
+	return &Watermark{
+		Image:   NewCellImageBytes(b, width, height),
+		Opacity: 0.3,
+		HAlign:  AlignCenter,
+		VAlign:  AlignMiddle,
+	}
+}
+
+/*
+Watermark is text or an image stamped on the pages of a document, such as a
+"DRAFT" mark or a faded logo. It is aligned within the margins of the page
+by HAlign and VAlign, rotated counterclockwise by Angle degrees around its
+centre, and drawn with an Opacity from 0 to 1; without an opacity it is
+opaque. Behind watermarks are drawn before the content of a page and the
+others over it, on the pages from FromPage to ToPage, which are unbounded
+when zero.
+*/
+type Watermark struct {
+	Text     string     `json:"text"`
+	Style    *FontStyle `json:"style"`
+	Image    *CellImage `json:"image"`
+	Angle    float64    `json:"angle"`
+	Opacity  float64    `json:"opacity"`
+	HAlign   string     `json:"h_align"`
+	VAlign   string     `json:"v_align"`
+	Behind   bool       `json:"behind"`
+	FromPage int        `json:"from_page"`
+	ToPage   int        `json:"to_page"`
+}
+
+func (w *Watermark) SetAngle(angle float64) {
+	w.Angle = angle
+}
+
+func (w *Watermark) SetOpacity(opacity float64) {
+	w.Opacity = opacity
+}
+
+func (w *Watermark) SetAlign(hAlign, vAlign string) {
+	w.HAlign = hAlign
+	w.VAlign = vAlign
+}
+
+func (w *Watermark) SetBehind(behind bool) {
+	w.Behind = behind
+}
+
+/*
+SetPages limits the watermark to the pages from one page to another,
+numbered from 1. A zero page leaves that end of the range open.
+*/
+func (w *Watermark) SetPages(from, to int) {
+	w.FromPage = from
+	w.ToPage = to
+}
+
+func (w *Watermark) onPage(page int) bool {
+	return (w.FromPage <= 0 || page >= w.FromPage) && (w.ToPage <= 0 || page <= w.ToPage)
+}
+
+/*
+AddWatermark adds a watermark to the pages of the document. Watermarks
+behind the content are drawn when a page starts, so add them before writing
+to the current page.
+*/
+func (p *PDF) AddWatermark(watermark *Watermark) {
+	p.watermarks = append(p.watermarks, watermark)
+	if page := p.Engine.PageNo(); watermark.Behind && watermark.onPage(page) {
+		p.drawWatermarks([]*Watermark{watermark})
+	}
+}
+
+/*
+pageWatermarks returns the watermarks of a page drawn behind or over its
+content.
+*/
+func (p *PDF) pageWatermarks(page int, behind bool) []*Watermark {
+	var watermarks []*Watermark
+	for _, w := range p.watermarks {
+		if w.Behind == behind && w.onPage(page) {
+			watermarks = append(watermarks, w)
+		}
+	}
+	return watermarks
+}
+
+/*
+drawWatermarks draws watermarks on the current page, keeping the position,
+the font and the text colour in use.
+*/
+func (p *PDF) drawWatermarks(watermarks []*Watermark) {
+	if len(watermarks) == 0 {
+		return
+	}
+	x, y := p.Engine.GetXY()
+	restoreFont := p.keepFont()
+	auto, margin := p.Engine.GetAutoPageBreak()
+	p.Engine.SetAutoPageBreak(false, margin)
+	for _, w := range watermarks {
+		w.draw(p)
+	}
+	p.Engine.SetAutoPageBreak(auto, margin)
+	p.Engine.SetXY(x, y)
+	restoreFont()
+}
+
+func (w *Watermark) draw(p *PDF) {
+	style := w.Style
+	if style == nil {
+		style = p.DefaultFontStyle
+	}
+	var width, height float64
+	if w.Image != nil {
+		width, height = w.Image.size(p, 0)
+	} else {
+		width, height = p.textMeasure(style)(p.resolvePlaceholders(w.Text)), p.lineHeight(style)
+	}
+	// Align the unrotated watermark within the margins of the page.
+	left, top := p.PageMarginLeft, p.PageMarginTop
+	right, bottom := p.PageWidth-p.PageMarginRight, p.PageHeight-p.PageMarginBottom
+	x, y := left+(right-left-width)/2, top+(bottom-top-height)/2
+	switch w.HAlign {
+	case AlignLeft:
+		x = left
+	case AlignRight:
+		x = right - width
+	}
+	switch w.VAlign {
+	case AlignTop:
+		y = top
+	case AlignBottom:
+		y = bottom - height
+	}
+	translucent := w.Opacity > 0 && w.Opacity < 1
+	if translucent {
+		p.Engine.SetAlpha(w.Opacity, "Normal")
+	}
+	p.Engine.TransformBegin()
+	if w.Angle != 0 {
+		p.Engine.TransformRotate(w.Angle, x+width/2, y+height/2)
+	}
+	if w.Image != nil {
+		w.Image.draw(p, x, y, width, height)
+	} else {
+		cm := p.Engine.GetCellMargin()
+		p.Engine.SetCellMargin(0)
+		p.Engine.SetXY(x, y)
+		p.drawLine(style, width, height, w.Text, AlignLeft)
+		p.Engine.SetCellMargin(cm)
+	}
+	p.Engine.TransformEnd()
+	if translucent {
+		p.Engine.SetAlpha(1, "Normal")
+	}
+}
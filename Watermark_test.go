@@ -0,0 +1,81 @@
+package gopdf
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var fontOp = regexp.MustCompile(`/(F\w+) [\d.]+ Tf`)
+
+/*
+lastFont returns the font resource and size last set in a content stream.
+*/
+func lastFont(content string) string {
+	m := fontOp.FindAllString(content, -1)
+	if m == nil {
+		return ""
+	}
+	return m[len(m)-1]
+}
+
+func TestWatermarkKeepsFontAndColor(t *testing.T) {
+	p := newTestPDF()
+	style := NewFontStyle(FontFamilyTimes, 20, 0, NewRGB(255, 0, 0), false, false, false)
+	p.WriteText("before", style)
+	mark := NewTextWatermark("DRAFT", NewFontStyle(FontFamilyHelvetica, 60, 0, NewRGB(128, 128, 128), true, false, false))
+	mark.SetBehind(true)
+	p.AddWatermark(mark)
+	p.WriteLink("link", "https://example.com", nil)
+	p.WriteTableRows(NewTable(nil).AddRow(NewCell("after", NewCellStyle(style, nil, nil, "", ""), 0, 0)))
+
+	content := pageOutput(t, p)[0]
+	font := lastFont(before(t, content, "before"))
+	if got := lastFont(before(t, content, "link")); got != font {
+		t.Errorf("link after the watermark is drawn in %s, want %s", got, font)
+	}
+	if got := lastFont(before(t, content, "after")); got != font {
+		t.Errorf("text after the watermark is drawn in %s, want %s", got, font)
+	}
+	// Colored text is drawn as "q r g b rg BT ... Tj ET Q".
+	after := before(t, content, "after")
+	if i := strings.LastIndex(after, "q "); i < 0 || !strings.HasPrefix(after[i:], "q 1.000 0.000 0.000 rg") {
+		t.Errorf("text after the watermark is not red:\n%s", content)
+	}
+}
+
+func TestWatermarkBeforeAnyText(t *testing.T) {
+	p := newTestPDF()
+	mark := NewTextWatermark("DRAFT", NewFontStyle(FontFamilyTimes, 60, 0, NewRGB(128, 128, 128), false, false, false))
+	mark.SetBehind(true)
+	p.AddWatermark(mark)
+	p.WriteText("body", nil)
+	content := pageOutput(t, p)[0]
+	if got := lastFont(before(t, content, "body")); !strings.HasSuffix(got, " 12.00 Tf") {
+		t.Errorf("body text is drawn with %s, want the default font", got)
+	}
+	if strings.Contains(before(t, content, "body")[strings.Index(content, "(DRAFT)Tj"):], " rg") {
+		t.Errorf("body text is drawn in the watermark colour")
+	}
+}
+
+func TestWatermarkPages(t *testing.T) {
+	p := newTestPDF()
+	mark := NewTextWatermark("COPY", nil)
+	mark.SetPages(2, 0)
+	p.AddWatermark(mark)
+	p.WriteText("one", nil)
+	p.AddPage()
+	p.WriteText("two", nil)
+	pages := pageOutput(t, p)
+	if strings.Contains(pages[0], "(COPY)Tj") {
+		t.Errorf("watermark drawn on page 1")
+	}
+	// Watermarks over the content are drawn after it, rotated and faded.
+	if i, j := strings.Index(pages[1], "(two)Tj"), strings.Index(pages[1], "(COPY)Tj"); j < i || i < 0 {
+		t.Errorf("watermark not drawn over page 2")
+	}
+	if !strings.Contains(pages[1], " cm") || !strings.Contains(pages[1], "/GS") {
+		t.Errorf("watermark is not rotated and translucent:\n%s", pages[1])
+	}
+}
@@ -0,0 +1,9 @@
+package gopdf
+
+const (
+	AggregateSum     = "sum"
+	AggregateCount   = "count"
+	AggregateAverage = "average"
+	AggregateMin     = "min"
+	AggregateMax     = "max"
+)
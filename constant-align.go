@@ -1,9 +1,10 @@
 package gopdf
 
 const (
-	AlignLeft   = "L"
-	AlignCenter = "C"
-	AlignRight  = "R"
+	AlignLeft    = "L"
+	AlignCenter  = "C"
+	AlignRight   = "R"
+	AlignJustify = "J"
 )
 
 const (
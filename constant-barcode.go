@@ -0,0 +1,5 @@
+package gopdf
+
+const (
+	BarcodeCode128 = "code128"
+)
@@ -0,0 +1,8 @@
+package gopdf
+
+const (
+	FaceRegular    = ""
+	FaceBold       = "B"
+	FaceItalic     = "I"
+	FaceBoldItalic = "BI"
+)
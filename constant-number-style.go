@@ -0,0 +1,9 @@
+package gopdf
+
+const (
+	NumberStyleArabic       = "arabic"
+	NumberStyleRomanLower   = "roman"
+	NumberStyleRomanUpper   = "ROMAN"
+	NumberStyleLettersLower = "letters"
+	NumberStyleLettersUpper = "LETTERS"
+)
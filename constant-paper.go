@@ -1,10 +1,31 @@
 package gopdf
 
 const (
-	PaperA3      = "A3"
-	PaperA4      = "A4"
-	PaperA5      = "A5"
-	PaperLetter  = "Letter"
-	PaperLegal   = "Legal"
-	PaperTabloid = "Tabloid"
+	PaperA0         = "A0"
+	PaperA1         = "A1"
+	PaperA2         = "A2"
+	PaperA3         = "A3"
+	PaperA4         = "A4"
+	PaperA5         = "A5"
+	PaperA6         = "A6"
+	PaperA7         = "A7"
+	PaperB4         = "B4"
+	PaperB5         = "B5"
+	PaperB6         = "B6"
+	PaperC4         = "C4"
+	PaperC5         = "C5"
+	PaperC6         = "C6"
+	PaperDL         = "DL"
+	PaperJISB4      = "JIS-B4"
+	PaperJISB5      = "JIS-B5"
+	PaperJISB6      = "JIS-B6"
+	PaperLetter     = "Letter"
+	PaperLegal      = "Legal"
+	PaperTabloid    = "Tabloid"
+	PaperHalfLetter = "HalfLetter"
+	PaperExecutive  = "Executive"
+	PaperANSIC      = "ANSI-C"
+	PaperANSID      = "ANSI-D"
+	PaperANSIE      = "ANSI-E"
+	PaperCustom     = "Custom"
 )
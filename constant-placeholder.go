@@ -0,0 +1,8 @@
+package gopdf
+
+const (
+	PlaceholderPage         = "{page}"          // number of the page in the document
+	PlaceholderPages        = "{pages}"         // number of pages of the document
+	PlaceholderSectionPage  = "{section_page}"  // label of the page in its numbering section
+	PlaceholderSectionPages = "{section_pages}" // number of pages of the numbering section
+)
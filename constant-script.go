@@ -0,0 +1,6 @@
+package gopdf
+
+const (
+	ScriptSuperscript = "super"
+	ScriptSubscript   = "sub"
+)
@@ -0,0 +1,7 @@
+package gopdf
+
+const (
+	TableThemeGrid    = "grid"
+	TableThemeStriped = "striped"
+	TableThemeMinimal = "minimal"
+)
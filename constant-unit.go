@@ -0,0 +1,8 @@
+package gopdf
+
+const (
+	UnitPoint      = "pt"
+	UnitMillimeter = "mm"
+	UnitCentimeter = "cm"
+	UnitInch       = "in"
+)
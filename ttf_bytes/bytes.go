@@ -1,11 +1,28 @@
 package ttf_bytes
 
 import (
-	"os"
-	"path"
-	"runtime"
+	"fmt"
+	"io/fs"
+	"sync"
 )
 
+// File names of the bundled fonts, for ReadFile.
+const (
+	FileNotoSansTCRegular = "NotoSansTC-Regular.ttf"
+	FileNotoSansTCBold    = "NotoSansTC-Bold.ttf"
+	FileNotoSansSCRegular = "NotoSansSC-Regular.ttf"
+	FileNotoSansSCBold    = "NotoSansSC-Bold.ttf"
+)
+
+/*
+The bytes of the bundled fonts, which used to be copied from the embedded
+files at init.
+
+Deprecated: the variables are no longer set, so that importing the package
+does not copy the fonts, and are always nil. Use NotoSansTCRegularBytes and
+the like, which read a font only when it is needed and report why a font is
+missing.
+*/
 var (
 	NotoSansTCRegular []byte
 	NotoSansTCBold    []byte
@@ -14,30 +31,77 @@ var (
 )
 
 var (
-	filepath string
+	notoSansTCRegular = bundledFont(FileNotoSansTCRegular)
+	notoSansTCBold    = bundledFont(FileNotoSansTCBold)
+	notoSansSCRegular = bundledFont(FileNotoSansSCRegular)
+	notoSansSCBold    = bundledFont(FileNotoSansSCBold)
 )
 
-func init() {
-	initFilepath()
-	NotoSansTCRegular = getBytes("NotoSansTC-Regular.ttf")
-	NotoSansTCBold = getBytes("NotoSansTC-Bold.ttf")
-	NotoSansSCRegular = getBytes("NotoSansSC-Regular.ttf")
-	NotoSansSCBold = getBytes("NotoSansSC-Bold.ttf")
+/*
+NotoSansTCRegularBytes returns the bytes of the bundled font, read on the
+first call and shared by later calls. They must not be modified.
+*/
+func NotoSansTCRegularBytes() ([]byte, error) {
+	return notoSansTCRegular()
+}
+
+/*
+NotoSansTCBoldBytes returns the bytes of the bundled font, read on the first
+call and shared by later calls. They must not be modified.
+*/
+func NotoSansTCBoldBytes() ([]byte, error) {
+	return notoSansTCBold()
+}
+
+/*
+NotoSansSCRegularBytes returns the bytes of the bundled font, read on the
+first call and shared by later calls. They must not be modified.
+*/
+func NotoSansSCRegularBytes() ([]byte, error) {
+	return notoSansSCRegular()
+}
+
+/*
+NotoSansSCBoldBytes returns the bytes of the bundled font, read on the first
+call and shared by later calls. They must not be modified.
+*/
+func NotoSansSCBoldBytes() ([]byte, error) {
+	return notoSansSCBold()
 }
 
-func initFilepath() {
-	_, filename, _, ok := runtime.Caller(0)
-	if ok {
-		filepath = path.Dir(filename) + "/"
-	} else {
-		panic("Cannot get current file path")
+/*
+bundledFont returns a function that reads a bundled font file on its first
+call and returns the same bytes, or the error of ReadFile, on later calls.
+*/
+func bundledFont(name string) func() ([]byte, error) {
+	return sync.OnceValues(func() ([]byte, error) {
+		return ReadFile(name)
+	})
+}
+
+/*
+ReadFile returns the bytes of a bundled font file, such as
+FileNotoSansTCRegular. The font files are not part of the source
+repository: they are embedded only when put in the fonts directory before
+building, and never with the gopdf_nocjk build tag, so by default the error
+of every font wraps fs.ErrNotExist. See fonts/README.md for how to bundle
+the fonts, or to register them at run time instead.
+*/
+func ReadFile(name string) ([]byte, error) {
+	b, err := fs.ReadFile(FS(), name)
+	if err != nil {
+		return nil, fmt.Errorf("bundled font %s is not included in this build (see ttf_bytes/fonts/README.md): %w", name, err)
 	}
+	return b, nil
 }
 
-func getBytes(filename string) []byte {
-	b, err := os.ReadFile(filepath + filename)
+/*
+FS returns the bundled font files.
+*/
+func FS() fs.FS {
+	sub, err := fs.Sub(fonts, "fonts")
 	if err != nil {
-		panic(err)
+		return fonts
 	}
-	return b
+	return sub
 }
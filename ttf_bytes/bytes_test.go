@@ -0,0 +1,52 @@
+package ttf_bytes
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"testing"
+)
+
+func TestBundledFonts(t *testing.T) {
+	for name, font := range map[string]struct {
+		b    []byte
+		read func() ([]byte, error)
+	}{
+		FileNotoSansTCRegular: {NotoSansTCRegular, NotoSansTCRegularBytes},
+		FileNotoSansTCBold:    {NotoSansTCBold, NotoSansTCBoldBytes},
+		FileNotoSansSCRegular: {NotoSansSCRegular, NotoSansSCRegularBytes},
+		FileNotoSansSCBold:    {NotoSansSCBold, NotoSansSCBoldBytes},
+	} {
+		read, readErr := font.read()
+		data, err := ReadFile(name)
+		if err != nil {
+			// The font files are supplied separately.
+			if !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("ReadFile(%q) error = %v, want fs.ErrNotExist", name, err)
+			}
+			if !errors.Is(readErr, fs.ErrNotExist) {
+				t.Errorf("%s: read error = %v, want fs.ErrNotExist", name, readErr)
+			}
+			if read != nil {
+				t.Errorf("%s: bytes read for a font that is not bundled", name)
+			}
+			continue
+		}
+		if font.b != nil {
+			t.Errorf("%s: deprecated variable set", name)
+		}
+		if !bytes.Equal(read, data) {
+			t.Errorf("%s: bytes differ from ReadFile", name)
+		}
+		if again, _ := font.read(); &again[0] != &read[0] {
+			t.Errorf("%s: font read again", name)
+		}
+	}
+}
+
+func TestReadFileMissing(t *testing.T) {
+	_, err := ReadFile("Missing.ttf")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("ReadFile error = %v, want fs.ErrNotExist", err)
+	}
+}
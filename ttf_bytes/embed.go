@@ -0,0 +1,10 @@
+//go:build !gopdf_nocjk
+
+package ttf_bytes
+
+import "embed"
+
+// The Noto CJK fonts are large; build with the gopdf_nocjk tag to leave them out.
+//
+//go:embed fonts
+var fonts embed.FS
@@ -0,0 +1,7 @@
+//go:build gopdf_nocjk
+
+package ttf_bytes
+
+import "embed"
+
+var fonts embed.FS